@@ -15,8 +15,12 @@ import (
 	httpapi "gamedivers.de/api/internal/adapters/http"
 	"gamedivers.de/api/internal/adapters/http/handlers"
 	"gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/adapters/stores/hltb"
 	"gamedivers.de/api/internal/adapters/stores/itad"
+	"gamedivers.de/api/internal/adapters/stores/steam"
 	"gamedivers.de/api/internal/config"
+	"gamedivers.de/api/internal/core/service"
+	"gamedivers.de/api/internal/jobs"
 	"gamedivers.de/api/internal/migrate"
 	"gamedivers.de/api/internal/ports/repo"
 	"github.com/joho/godotenv"
@@ -31,7 +35,10 @@ func main() {
 
 	cfg := config.Load()
 
+	healthHandler := &handlers.HealthHandler{Checks: map[string]handlers.HealthCheck{}}
+
 	var appRepo repo.Repo
+	var migrationRunner *migrate.Runner
 	if strings.TrimSpace(cfg.DatabaseURL) != "" {
 		db, err := postgres.Open(cfg.DatabaseURL)
 		if err != nil {
@@ -39,7 +46,7 @@ func main() {
 		}
 
 		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		err = migrate.Run(migrateCtx, db)
+		applied, err := migrate.Apply(migrateCtx, db)
 		migrateCancel()
 		if err != nil {
 			_ = db.Close()
@@ -47,10 +54,12 @@ func main() {
 		}
 
 		appRepo = &postgres.Repo{DB: db}
+		migrationRunner = &migrate.Runner{DB: db}
+		healthHandler.Checks["database"] = db.PingContext
 		defer func() {
 			_ = db.Close()
 		}()
-		log.Printf("database connected and migrations applied")
+		log.Printf("database connected, %d new migrations applied", applied)
 	} else {
 		log.Printf("DATABASE_URL not set, running without persistence")
 	}
@@ -58,12 +67,26 @@ func main() {
 	// Initialize ITAD client with API key
 	itadClient := itad.New(cfg.ITADAPIKey)
 	itadHandler := &handlers.ITADHandler{
-		Client: itadClient,
+		Client:         itadClient,
+		Users:          appRepo,
+		DefaultCountry: cfg.DefaultCountry,
+	}
+	if cfg.HLTBEnabled {
+		itadHandler.Completion = hltb.New()
 	}
 
 	// Initialize game handler
 	gameHandler := &handlers.GameHandler{
-		Repo: appRepo,
+		Repo:       appRepo,
+		GameRoots:  cfg.GameRoots,
+		SteamRoots: cfg.SteamRoots,
+	}
+
+	// OAuth state shared by the store login flows
+	var oauthStates handlers.StateStore = handlers.NewMemoryStateStore(10 * time.Minute)
+	if appRepo != nil {
+		// Login and callback may hit different replicas, so they share state through the database.
+		oauthStates = handlers.NewRepoStateStore(appRepo, 10*time.Minute)
 	}
 
 	// Initialize Steam handler
@@ -72,7 +95,9 @@ func main() {
 		cfg.SteamCallbackURL,
 		cfg.FrontendOrigin,
 		appRepo,
+		oauthStates,
 	)
+	steamHandler.DefaultCountry = cfg.DefaultCountry
 
 	// Initialize Epic Games handler
 	epicHandler := handlers.NewEpicHandler(
@@ -80,7 +105,13 @@ func main() {
 		cfg.EpicClientSecret,
 		cfg.EpicCallbackURL,
 		cfg.FrontendOrigin,
+		oauthStates,
 	)
+	epicHandler.SetRateLimit(cfg.EpicRateLimit, cfg.EpicRateBurst)
+	if appRepo != nil {
+		// Replicas share exchange codes through the database; the callback and the redeem may hit different pods.
+		epicHandler.SetExchangeStore(handlers.NewRepoExchangeStore(appRepo, 0))
+	}
 
 	// Initialize Keycloak client
 	keycloakClient := keycloak.NewClient(
@@ -95,16 +126,58 @@ func main() {
 	authHandler := &handlers.AuthHandler{
 		Keycloak: keycloakClient,
 		Repo:     appRepo,
+		PasswordPolicy: handlers.PasswordPolicy{
+			MinLength:  cfg.PasswordMinLength,
+			RequireMix: cfg.PasswordRequireMix,
+		},
 	}
 
+	// Initialize personal access token and admin handlers
+	apiTokenHandler := &handlers.APITokenHandler{}
+	adminHandler := &handlers.AdminHandler{SteamAppDetails: steamHandler.AppDetailsState}
+	priceHandler := &handlers.PriceHandler{DefaultCountry: cfg.DefaultCountry}
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
 	// Initialize JWT middleware for token validation
 	jwtMiddleware := middleware.NewJWTMiddleware(
 		keycloakClient.GetJWKSURL(),
 		keycloakClient.GetIssuer(),
 		cfg.KeycloakClientID,
 	)
+	healthHandler.Checks["keycloak"] = jwtMiddleware.CheckJWKS
+	if appRepo != nil {
+		apiTokenHandler.Repo = appRepo
+		adminHandler.Catalog = appRepo
+		adminHandler.Migrations = migrationRunner
+		priceHandler.Repo = appRepo
+		// Steam rate limits appdetails per IP, so the pricing client shares the handler's breaker.
+		steamPricing := steam.New()
+		steamHandler.ShareAppDetailsBreaker(steamPricing)
+		priceHandler.Pricing = &service.PricingService{
+			Repo:    appRepo,
+			Steam:   steamPricing,
+			TTL:     6 * time.Hour,
+			NowUnix: func() int64 { return time.Now().Unix() },
+		}
+		jwtMiddleware.WithAPITokens(appRepo)
+
+		priceUpdater := &jobs.DailyUpdater{
+			Repo:             appRepo,
+			Pricing:          priceHandler.Pricing,
+			Interval:         time.Hour,
+			Batch:            200,
+			StaleAfter:       time.Duration(cfg.PriceStaleHours) * time.Hour,
+			HistoryRetention: 365 * 24 * time.Hour,
+		}
+		adminHandler.PriceRefresh = priceUpdater
+		go priceUpdater.Run(jobsCtx)
+	}
 
-	router := httpapi.Router(cfg.FrontendOrigin, itadHandler, gameHandler, steamHandler, epicHandler, authHandler, jwtMiddleware)
+	corsOrigins := append([]string{cfg.FrontendOrigin}, cfg.CORSOrigins...)
+	router := httpapi.Router(corsOrigins, int64(cfg.MaxBodyBytes), itadHandler, gameHandler, steamHandler, epicHandler, authHandler, apiTokenHandler, adminHandler, priceHandler, healthHandler, jwtMiddleware)
 
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -123,6 +196,7 @@ func main() {
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	<-sigCh
 	log.Printf("shutting down...")
+	stopJobs()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
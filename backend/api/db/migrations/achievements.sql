@@ -0,0 +1,21 @@
+-- Achievement totals per game (from the store's achievement schema).
+CREATE TABLE IF NOT EXISTS game_achievements (
+  store_id TEXT NOT NULL,
+  external_game_id TEXT NOT NULL,
+  total INTEGER NOT NULL,
+  updated_at INTEGER NOT NULL,
+  PRIMARY KEY (store_id, external_game_id)
+);
+
+-- Unlocked achievement counts per user and game.
+CREATE TABLE IF NOT EXISTS user_achievements (
+  user_id TEXT NOT NULL,
+  store_id TEXT NOT NULL,
+  external_game_id TEXT NOT NULL,
+  unlocked INTEGER NOT NULL,
+  updated_at INTEGER NOT NULL,
+  PRIMARY KEY (user_id, store_id, external_game_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_achievements_user
+  ON user_achievements(user_id);
@@ -0,0 +1,13 @@
+-- Personal access tokens for third-party integrations. Only the SHA-256 hash is stored.
+CREATE TABLE IF NOT EXISTS api_tokens (
+  id TEXT PRIMARY KEY,
+  user_id TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  label TEXT NOT NULL,
+  scopes TEXT NOT NULL,                  -- space-separated, e.g. "library:read prices:read"
+  created_at INTEGER NOT NULL,
+  last_used_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user
+  ON api_tokens(user_id);
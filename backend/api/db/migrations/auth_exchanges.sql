@@ -0,0 +1,12 @@
+-- One-time codes an OAuth callback hands to the frontend, redeemable on any API replica.
+-- Only the SHA-256 hash of the code is stored; rows live for minutes and are deleted on redeem.
+CREATE TABLE IF NOT EXISTS auth_exchanges (
+  code_hash TEXT PRIMARY KEY,
+  account_id TEXT NOT NULL,
+  username TEXT NOT NULL,
+  access_token TEXT NOT NULL,
+  expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_exchanges_expires
+  ON auth_exchanges(expires_at);
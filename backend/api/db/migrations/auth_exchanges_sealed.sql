@@ -0,0 +1,4 @@
+-- Exchange rows now hold the provider token encrypted with a key derived from the one-time code,
+-- which is never stored. Rows written before the change are dropped; they expire within minutes anyway.
+DELETE FROM auth_exchanges;
+ALTER TABLE auth_exchanges RENAME COLUMN access_token TO sealed_access_token;
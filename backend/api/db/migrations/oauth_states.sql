@@ -0,0 +1,10 @@
+-- Issued OAuth/OpenID state values, shared so a login started on one API replica can finish on another.
+CREATE TABLE IF NOT EXISTS oauth_states (
+  state TEXT PRIMARY KEY,
+  provider TEXT NOT NULL,
+  user_id TEXT NOT NULL DEFAULT '',      -- set when the login links a store account to a signed-in user
+  expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires
+  ON oauth_states(expires_at);
@@ -0,0 +1,14 @@
+-- Price changes over time. A row is appended only when the fetched price differs from the stored one.
+CREATE TABLE IF NOT EXISTS price_history (
+  store_id TEXT NOT NULL,
+  external_game_id TEXT NOT NULL,
+  cc TEXT NOT NULL,
+  currency TEXT,
+  initial_cents INTEGER NOT NULL,
+  final_cents INTEGER NOT NULL,
+  discount_percent INTEGER NOT NULL,
+  recorded_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_game
+  ON price_history(store_id, external_game_id, cc, recorded_at);
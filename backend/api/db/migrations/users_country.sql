@@ -0,0 +1,7 @@
+-- Preferred store region per user (ISO 3166-1 alpha-2, e.g. "DE").
+CREATE TABLE IF NOT EXISTS users (
+  id TEXT PRIMARY KEY,
+  created_at INTEGER NOT NULL
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS country TEXT;
@@ -4,15 +4,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 )
 
+var (
+	// ErrUserExists is returned by Register when the username or email is already in use.
+	ErrUserExists = errors.New("user already exists")
+	// ErrUsernameTaken and ErrEmailTaken narrow ErrUserExists down to the conflicting field.
+	ErrUsernameTaken = fmt.Errorf("%w: username taken", ErrUserExists)
+	ErrEmailTaken    = fmt.Errorf("%w: email taken", ErrUserExists)
+	// ErrIncorrectPassword is returned when a password confirmation does not match.
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	// ErrUserNotFound is returned by admin user operations when the user does not exist.
+	ErrUserNotFound = errors.New("keycloak user not found")
+	// ErrAdminForbidden means the service account lacks the realm-management role for the operation.
+	ErrAdminForbidden = errors.New("keycloak admin permission denied")
+)
+
 // Client handles communication with Keycloak Admin and Token APIs
 type Client struct {
 	baseURL              string
@@ -184,7 +200,7 @@ func (c *Client) Register(ctx context.Context, req RegisterRequest) (*UserRepres
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusConflict {
-		return nil, fmt.Errorf("user already exists")
+		return nil, registerConflictError(resp.Body)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
@@ -193,15 +209,11 @@ func (c *Client) Register(ctx context.Context, req RegisterRequest) (*UserRepres
 	}
 
 	// Get the created user ID from the Location header
-	location := resp.Header.Get("Location")
-	if location == "" {
-		return nil, fmt.Errorf("no location header in response")
+	userID, err := userIDFromLocation(resp.Header.Get("Location"))
+	if err != nil {
+		return nil, err
 	}
 
-	// Extract user ID from location URL
-	parts := strings.Split(location, "/")
-	userID := parts[len(parts)-1]
-
 	verificationEmailSent := !c.requireEmailVerified
 	verificationEmailWarning := ""
 
@@ -233,6 +245,49 @@ func (c *Client) Register(ctx context.Context, req RegisterRequest) (*UserRepres
 	}, nil
 }
 
+// registerConflictError tells a taken username from a taken email using the admin API's
+// message ("User exists with same username" / "... same email").
+func registerConflictError(body io.Reader) error {
+	var conflict struct {
+		ErrorMessage string `json:"errorMessage"`
+	}
+	_ = json.NewDecoder(io.LimitReader(body, 4096)).Decode(&conflict)
+
+	msg := strings.ToLower(conflict.ErrorMessage)
+	switch {
+	case strings.Contains(msg, "username"):
+		return ErrUsernameTaken
+	case strings.Contains(msg, "email"):
+		return ErrEmailTaken
+	default:
+		return ErrUserExists
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// userIDFromLocation extracts the created user's UUID from the Location header
+// returned by the admin users endpoint (".../admin/realms/{realm}/users/{id}").
+func userIDFromLocation(location string) (string, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return "", fmt.Errorf("no location header in response")
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("parse location header: %w", err)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	userID := segments[len(segments)-1]
+	if !uuidPattern.MatchString(userID) {
+		return "", fmt.Errorf("no user id in location header")
+	}
+
+	return userID, nil
+}
+
 func (c *Client) setEmailVerified(ctx context.Context, userID string, verified bool, adminToken string) error {
 	if adminToken == "" {
 		var err error
@@ -691,7 +746,7 @@ func (c *Client) ChangePassword(ctx context.Context, userID string, currentPassw
 		Password: currentPassword,
 	})
 	if err != nil {
-		return fmt.Errorf("current password is incorrect")
+		return ErrIncorrectPassword
 	}
 
 	// Set new password via Admin API
@@ -729,6 +784,92 @@ func (c *Client) ChangePassword(ctx context.Context, userID string, currentPassw
 	return nil
 }
 
+// VerifyPassword checks password against the user's credentials by attempting a login.
+func (c *Client) VerifyPassword(ctx context.Context, userID, password string) error {
+	adminToken, err := c.getAdminToken(ctx)
+	if err != nil {
+		return fmt.Errorf("get admin token: %w", err)
+	}
+
+	userURL := fmt.Sprintf("%s/admin/realms/%s/users/%s", c.baseURL, c.realm, userID)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, userURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+adminToken)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get user failed (status %d)", resp.StatusCode)
+	}
+
+	var user UserRepresentation
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return fmt.Errorf("parse user: %w", err)
+	}
+
+	if _, err := c.Login(ctx, LoginRequest{Username: user.Username, Password: password}); err != nil {
+		return ErrIncorrectPassword
+	}
+	return nil
+}
+
+// DeleteUser removes the user from the realm.
+func (c *Client) DeleteUser(ctx context.Context, userID string) error {
+	return c.adminUserRequest(ctx, http.MethodDelete, userID, nil)
+}
+
+// SetEnabled enables or disables the user. Disabled users cannot log in or refresh tokens.
+func (c *Client) SetEnabled(ctx context.Context, userID string, enabled bool) error {
+	body, err := json.Marshal(map[string]bool{"enabled": enabled})
+	if err != nil {
+		return fmt.Errorf("marshal user: %w", err)
+	}
+	return c.adminUserRequest(ctx, http.MethodPut, userID, body)
+}
+
+// adminUserRequest sends method to the admin endpoint of userID, mapping 404 to ErrUserNotFound
+// and 401/403 to ErrAdminForbidden.
+func (c *Client) adminUserRequest(ctx context.Context, method, userID string, body []byte) error {
+	adminToken, err := c.getAdminToken(ctx)
+	if err != nil {
+		return fmt.Errorf("get admin token: %w", err)
+	}
+
+	userURL := fmt.Sprintf("%s/admin/realms/%s/users/%s", c.baseURL, c.realm, url.PathEscape(userID))
+	httpReq, err := http.NewRequestWithContext(ctx, method, userURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+adminToken)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrUserNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w (status %d)", ErrAdminForbidden, resp.StatusCode)
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("keycloak error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+}
+
 // UpdateProfile updates the user's profile information
 func (c *Client) UpdateProfile(ctx context.Context, userID string, req UpdateProfileRequest) (*UserRepresentation, error) {
 	// Get admin token
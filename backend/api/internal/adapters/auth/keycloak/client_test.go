@@ -0,0 +1,160 @@
+package keycloak
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUserIDFromLocation(t *testing.T) {
+	const id = "3f2b8c1e-9a4d-4e2f-8b1a-0c9d7e6f5a4b"
+
+	cases := []struct {
+		name     string
+		location string
+		want     string
+		wantErr  bool
+	}{
+		{name: "normal", location: "https://auth.example.com/admin/realms/demo/users/" + id, want: id},
+		{name: "trailing slash", location: "https://auth.example.com/admin/realms/demo/users/" + id + "/", want: id},
+		{name: "relative", location: "/admin/realms/demo/users/" + id, want: id},
+		{name: "missing", location: "", wantErr: true},
+		{name: "no uuid", location: "https://auth.example.com/admin/realms/demo/users/", wantErr: true},
+		{name: "garbage segment", location: "https://auth.example.com/admin/realms/demo/users/not-a-uuid", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := userIDFromLocation(tc.location)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got user id %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected user id %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterFailsWithoutLocationHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token"):
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"admin-token","expires_in":60,"token_type":"Bearer"}`))
+		case strings.HasSuffix(r.URL.Path, "/users") && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "demo", "api", "secret", true)
+	_, err := client.Register(context.Background(), RegisterRequest{
+		Username: "player",
+		Email:    "player@example.com",
+		Password: "correct-horse",
+	})
+	if err == nil || !strings.Contains(err.Error(), "location header") {
+		t.Fatalf("expected location header error, got %v", err)
+	}
+}
+
+func TestRegisterConflictNamesField(t *testing.T) {
+	cases := map[string]error{
+		`{"errorMessage":"User exists with same username"}`: ErrUsernameTaken,
+		`{"errorMessage":"User exists with same email"}`:    ErrEmailTaken,
+		``: ErrUserExists,
+	}
+	for body, want := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token") {
+				_, _ = w.Write([]byte(`{"access_token":"admin-token","expires_in":60,"token_type":"Bearer"}`))
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(body))
+		}))
+
+		client := NewClient(server.URL, "demo", "api", "secret", true)
+		_, err := client.Register(context.Background(), RegisterRequest{Username: "player", Email: "player@example.com", Password: "correct-horse"})
+		server.Close()
+
+		if !errors.Is(err, want) || !errors.Is(err, ErrUserExists) {
+			t.Fatalf("body %q: expected %v, got %v", body, want, err)
+		}
+	}
+}
+
+func newAdminTestClient(t *testing.T, status int, seen *[]string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token") {
+			_, _ = w.Write([]byte(`{"access_token":"admin-token","expires_in":60,"token_type":"Bearer"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer admin-token" {
+			t.Errorf("expected admin token, got %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		*seen = append(*seen, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, "demo", "api", "secret", true)
+}
+
+func TestDeleteUser(t *testing.T) {
+	var seen []string
+	client := newAdminTestClient(t, http.StatusNoContent, &seen)
+
+	if err := client.DeleteUser(context.Background(), "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 1 || seen[0] != "DELETE /admin/realms/demo/users/user-1 " {
+		t.Fatalf("unexpected requests %q", seen)
+	}
+}
+
+func TestSetEnabled(t *testing.T) {
+	var seen []string
+	client := newAdminTestClient(t, http.StatusNoContent, &seen)
+
+	if err := client.SetEnabled(context.Background(), "user-1", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 1 || seen[0] != `PUT /admin/realms/demo/users/user-1 {"enabled":false}` {
+		t.Fatalf("unexpected requests %q", seen)
+	}
+}
+
+func TestAdminUserErrors(t *testing.T) {
+	cases := map[int]error{
+		http.StatusNotFound:     ErrUserNotFound,
+		http.StatusForbidden:    ErrAdminForbidden,
+		http.StatusUnauthorized: ErrAdminForbidden,
+	}
+	for status, want := range cases {
+		var seen []string
+		client := newAdminTestClient(t, status, &seen)
+
+		if err := client.DeleteUser(context.Background(), "user-1"); !errors.Is(err, want) {
+			t.Fatalf("DeleteUser status %d: expected %v, got %v", status, want, err)
+		}
+		if err := client.SetEnabled(context.Background(), "user-1", true); !errors.Is(err, want) {
+			t.Fatalf("SetEnabled status %d: expected %v, got %v", status, want, err)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -98,7 +100,32 @@ WHERE store_id=$1 AND external_game_id=$2 AND cc=$3
 }
 
 func (r *Repo) UpsertPriceAndLowest(ctx context.Context, p repo.UpsertPriceParams) error {
-	_, err := r.DB.ExecContext(ctx, `
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Record a history entry only when the price differs from the one currently stored.
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO price_history(
+  store_id, external_game_id, cc, currency,
+  initial_cents, final_cents, discount_percent, recorded_at
+)
+SELECT $1,$2,$3,$4,$5,$6,$7,$8
+WHERE NOT EXISTS (
+  SELECT 1 FROM prices
+  WHERE store_id=$1 AND external_game_id=$2 AND cc=$3
+    AND currency IS NOT DISTINCT FROM $4
+    AND current_initial_cents IS NOT DISTINCT FROM $5
+    AND current_final_cents IS NOT DISTINCT FROM $6
+)
+`, p.StoreID, p.ExternalGameID, p.CC, p.Currency,
+		p.InitialCents, p.FinalCents, p.DiscountPercent, p.FetchedAtUnix); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
 INSERT INTO prices(
   store_id, external_game_id, cc, currency,
   current_initial_cents, current_final_cents, current_discount_percent,
@@ -126,8 +153,96 @@ ON CONFLICT(store_id, external_game_id, cc) DO UPDATE SET
     END
 `, p.StoreID, p.ExternalGameID, p.CC, p.Currency,
 		p.InitialCents, p.FinalCents, p.DiscountPercent,
-		p.FetchedAtUnix, p.FinalCents, p.FetchedAtUnix)
-	return err
+		p.FetchedAtUnix, p.FinalCents, p.FetchedAtUnix); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// currentPricesChunk keeps each price lookup well below Postgres' bind parameter limit.
+const currentPricesChunk = 1000
+
+func (r *Repo) ListCurrentPrices(ctx context.Context, storeID, cc string, externalGameIDs []string) (map[string]int64, error) {
+	out := make(map[string]int64, len(externalGameIDs))
+	for start := 0; start < len(externalGameIDs); start += currentPricesChunk {
+		chunk := externalGameIDs[start:min(start+currentPricesChunk, len(externalGameIDs))]
+		placeholders, args := batchValues(chunk, "$%d", storeID, cc)
+
+		rows, err := r.DB.QueryContext(ctx, `
+SELECT external_game_id, current_final_cents
+FROM prices
+WHERE store_id=$1 AND cc=$2 AND current_final_cents IS NOT NULL
+  AND external_game_id IN (`+placeholders+`)
+`, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var id string
+			var cents int64
+			if err := rows.Scan(&id, &cents); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			out[id] = cents
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *Repo) ListPriceHistory(ctx context.Context, storeID, externalGameID, cc string, sinceUnix int64) ([]repo.PricePoint, error) {
+	// Rows are only written when the price changes, so the last row before sinceUnix is the
+	// price that was in effect at the start of the window.
+	rows, err := r.DB.QueryContext(ctx, `
+(SELECT COALESCE(currency, ''), initial_cents, final_cents, discount_percent, recorded_at
+ FROM price_history
+ WHERE store_id=$1 AND external_game_id=$2 AND cc=$3 AND recorded_at < $4
+ ORDER BY recorded_at DESC
+ LIMIT 1)
+UNION ALL
+(SELECT COALESCE(currency, ''), initial_cents, final_cents, discount_percent, recorded_at
+ FROM price_history
+ WHERE store_id=$1 AND external_game_id=$2 AND cc=$3 AND recorded_at >= $4)
+ORDER BY recorded_at ASC
+`, storeID, externalGameID, cc, sinceUnix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []repo.PricePoint
+	for rows.Next() {
+		var point repo.PricePoint
+		if err := rows.Scan(&point.Currency, &point.InitialCents, &point.FinalCents, &point.DiscountPercent, &point.RecordedAtUnix); err != nil {
+			return nil, err
+		}
+		out = append(out, point)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repo) PrunePriceHistory(ctx context.Context, beforeUnix int64) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, `
+DELETE FROM price_history h
+WHERE h.recorded_at < $1
+  AND EXISTS (
+    SELECT 1 FROM price_history newer
+    WHERE newer.store_id=h.store_id AND newer.external_game_id=h.external_game_id
+      AND newer.cc=h.cc AND newer.recorded_at > h.recorded_at
+  )
+`, beforeUnix)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 func (r *Repo) GetPriceRow(ctx context.Context, storeID, externalGameID, cc string) (*repo.PriceRow, bool, error) {
@@ -195,18 +310,55 @@ ON CONFLICT(id) DO NOTHING
 
 func (r *Repo) GetUser(ctx context.Context, userID string) (*repo.User, error) {
 	var user repo.User
+	var country sql.NullString
 	err := r.DB.QueryRowContext(ctx, `
-SELECT id, created_at FROM users WHERE id=$1
-`, userID).Scan(&user.ID, &user.CreatedAt)
+SELECT id, created_at, country FROM users WHERE id=$1
+`, userID).Scan(&user.ID, &user.CreatedAt, &country)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	if country.Valid {
+		user.Country = country.String
+	}
 	return &user, nil
 }
 
+func (r *Repo) SetUserCountry(ctx context.Context, userID, country string, nowUnix int64) error {
+	_, err := r.DB.ExecContext(ctx, `
+INSERT INTO users(id, created_at, country)
+VALUES ($1, $2, $3)
+ON CONFLICT(id) DO UPDATE SET
+  country=excluded.country
+`, userID, nowUnix, country)
+	return err
+}
+
+// userOwnedTables lists every table keyed by user_id; DeleteUserData clears them before the users row.
+var userOwnedTables = []string{"user_watchlist", "user_achievements", "api_tokens", "oauth_states"}
+
+func (r *Repo) DeleteUserData(ctx context.Context, userID string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range userOwnedTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE user_id=$1`, userID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id=$1`, userID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *Repo) AddWatch(ctx context.Context, userID, storeID, externalGameID, cc string, nowUnix int64) error {
 	_, err := r.DB.ExecContext(ctx, `
 INSERT INTO user_watchlist(user_id, store_id, external_game_id, cc, added_at)
@@ -216,6 +368,54 @@ ON CONFLICT(user_id, store_id, external_game_id, cc) DO NOTHING
 	return err
 }
 
+func (r *Repo) AddWatchBatch(ctx context.Context, userID, storeID string, externalGameIDs []string, cc string, nowUnix int64) error {
+	if len(externalGameIDs) == 0 {
+		return nil
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// $1..$4 are shared by every row; external game ids follow from $5.
+	values, args := batchValues(externalGameIDs, "($1, $%d, $2, $3, $4)", storeID, cc, nowUnix, userID)
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO tracked_games(store_id, external_game_id, cc, added_at)
+SELECT DISTINCT v.store_id, v.external_game_id, v.cc, v.added_at
+FROM (VALUES `+values+`) AS v(store_id, external_game_id, cc, added_at, user_id)
+ON CONFLICT(store_id, external_game_id, cc) DO NOTHING
+`, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO user_watchlist(user_id, store_id, external_game_id, cc, added_at)
+SELECT DISTINCT v.user_id, v.store_id, v.external_game_id, v.cc, v.added_at
+FROM (VALUES `+values+`) AS v(store_id, external_game_id, cc, added_at, user_id)
+ON CONFLICT(user_id, store_id, external_game_id, cc) DO NOTHING
+`, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// batchValues expands rowFormat (with one %d for the row's own parameter) once per id and
+// returns the VALUES list with shared parameters first, followed by the ids.
+func batchValues(ids []string, rowFormat string, shared ...any) (string, []any) {
+	rows := make([]string, len(ids))
+	args := make([]any, 0, len(shared)+len(ids))
+	args = append(args, shared...)
+	for i, id := range ids {
+		rows[i] = fmt.Sprintf(rowFormat, len(shared)+i+1)
+		args = append(args, id)
+	}
+	return strings.Join(rows, ", "), args
+}
+
 func (r *Repo) RemoveWatch(ctx context.Context, userID, storeID, externalGameID, cc string) error {
 	_, err := r.DB.ExecContext(ctx, `
 DELETE FROM user_watchlist
@@ -224,31 +424,265 @@ WHERE user_id=$1 AND store_id=$2 AND external_game_id=$3 AND cc=$4
 	return err
 }
 
-func (r *Repo) ListWatchedUniqueGamesForRefresh(ctx context.Context, storeID, cc string, limit int) ([]string, error) {
+func (r *Repo) ListStaleTrackedPrices(ctx context.Context, storeID string, staleBeforeUnix int64, limit int) ([]repo.TrackedPrice, error) {
+	// games.updated_at is bumped on every fetch, so games Steam has no price for aren't retried every cycle.
 	rows, err := r.DB.QueryContext(ctx, `
-SELECT uw.external_game_id
-FROM (
-  SELECT DISTINCT external_game_id
-  FROM user_watchlist
-  WHERE store_id=$1 AND cc=$2
-) AS uw
+SELECT t.external_game_id, t.cc
+FROM tracked_games t
 LEFT JOIN prices p
-  ON p.store_id=$1 AND p.external_game_id=uw.external_game_id AND p.cc=$2
-ORDER BY COALESCE(p.fetched_at, 0) ASC
+  ON p.store_id=t.store_id AND p.external_game_id=t.external_game_id AND p.cc=t.cc
+LEFT JOIN games g
+  ON g.store_id=t.store_id AND g.external_game_id=t.external_game_id
+WHERE t.store_id=$1
+  AND COALESCE(p.fetched_at, g.updated_at, 0) < $2
+ORDER BY COALESCE(p.fetched_at, g.updated_at, 0) ASC, t.added_at ASC
 LIMIT $3
-`, storeID, cc, limit)
+`, storeID, staleBeforeUnix, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var out []string
+	var out []repo.TrackedPrice
 	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
+		var tp repo.TrackedPrice
+		if err := rows.Scan(&tp.ExternalGameID, &tp.CC); err != nil {
 			return nil, err
 		}
-		out = append(out, id)
+		out = append(out, tp)
 	}
 	return out, rows.Err()
 }
+
+func (r *Repo) UpsertAchievementCounts(ctx context.Context, p repo.UpsertAchievementParams) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO game_achievements(store_id, external_game_id, total, updated_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT(store_id, external_game_id) DO UPDATE SET
+  total=excluded.total,
+  updated_at=excluded.updated_at
+`, p.StoreID, p.ExternalGameID, p.Total, p.UpdatedAtUnix); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO user_achievements(user_id, store_id, external_game_id, unlocked, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT(user_id, store_id, external_game_id) DO UPDATE SET
+  unlocked=excluded.unlocked,
+  updated_at=excluded.updated_at
+`, p.UserID, p.StoreID, p.ExternalGameID, p.Unlocked, p.UpdatedAtUnix); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repo) ForEachCatalogRow(ctx context.Context, fn func(repo.CatalogRow) error) error {
+	rows, err := r.DB.QueryContext(ctx, `
+SELECT g.store_id, g.external_game_id, g.name, COALESCE(g.type, ''),
+       COALESCE(p.cc, ''), COALESCE(p.currency, ''),
+       p.current_initial_cents, p.current_final_cents, p.current_discount_percent,
+       p.lowest_final_cents, p.fetched_at
+FROM games g
+LEFT JOIN prices p
+  ON p.store_id=g.store_id AND p.external_game_id=g.external_game_id
+ORDER BY g.store_id, g.external_game_id, p.cc
+`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row repo.CatalogRow
+		var initial, final, discount, lowest, fetchedAt sql.NullInt64
+		if err := rows.Scan(
+			&row.StoreID, &row.ExternalGameID, &row.Name, &row.Type,
+			&row.CC, &row.Currency,
+			&initial, &final, &discount, &lowest, &fetchedAt,
+		); err != nil {
+			return err
+		}
+		row.InitialCents = nullInt64Ptr(initial)
+		row.FinalCents = nullInt64Ptr(final)
+		row.DiscountPercent = nullInt64Ptr(discount)
+		row.LowestCents = nullInt64Ptr(lowest)
+		row.FetchedAtUnix = nullInt64Ptr(fetchedAt)
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	out := v.Int64
+	return &out
+}
+
+func (r *Repo) CreateAPIToken(ctx context.Context, p repo.CreateAPITokenParams) error {
+	_, err := r.DB.ExecContext(ctx, `
+INSERT INTO api_tokens(id, user_id, token_hash, label, scopes, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, p.ID, p.UserID, p.TokenHash, p.Label, strings.Join(p.Scopes, " "), p.CreatedAtUnix)
+	return err
+}
+
+func (r *Repo) ListAPITokens(ctx context.Context, userID string) ([]repo.APIToken, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+SELECT id, user_id, label, scopes, created_at, last_used_at
+FROM api_tokens
+WHERE user_id=$1
+ORDER BY created_at ASC
+`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []repo.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *token)
+	}
+	return out, rows.Err()
+}
+
+func (r *Repo) RevokeAPIToken(ctx context.Context, userID, tokenID string) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `
+DELETE FROM api_tokens
+WHERE user_id=$1 AND id=$2
+`, userID, tokenID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *Repo) GetAPITokenByHash(ctx context.Context, tokenHash string) (*repo.APIToken, bool, error) {
+	row := r.DB.QueryRowContext(ctx, `
+SELECT id, user_id, label, scopes, created_at, last_used_at
+FROM api_tokens
+WHERE token_hash=$1
+`, tokenHash)
+
+	token, err := scanAPIToken(row)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return token, true, nil
+}
+
+func (r *Repo) TouchAPIToken(ctx context.Context, tokenID string, nowUnix int64) error {
+	_, err := r.DB.ExecContext(ctx, `
+UPDATE api_tokens SET last_used_at=$2 WHERE id=$1
+`, tokenID, nowUnix)
+	return err
+}
+
+func scanAPIToken(row interface{ Scan(dest ...any) error }) (*repo.APIToken, error) {
+	var token repo.APIToken
+	var scopes string
+	var lastUsed sql.NullInt64
+	if err := row.Scan(&token.ID, &token.UserID, &token.Label, &scopes, &token.CreatedAt, &lastUsed); err != nil {
+		return nil, err
+	}
+	token.Scopes = strings.Fields(scopes)
+	if lastUsed.Valid {
+		v := lastUsed.Int64
+		token.LastUsedAt = &v
+	}
+	return &token, nil
+}
+
+func (r *Repo) PutAuthExchange(ctx context.Context, rec repo.AuthExchangeRecord, nowUnix int64) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM auth_exchanges WHERE expires_at < $1`, nowUnix); err != nil {
+		return err
+	}
+	_, err := r.DB.ExecContext(ctx, `
+INSERT INTO auth_exchanges(code_hash, account_id, username, sealed_access_token, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`, rec.CodeHash, rec.AccountID, rec.Username, rec.SealedAccessToken, rec.ExpiresAtUnix)
+	return err
+}
+
+func (r *Repo) TakeAuthExchange(ctx context.Context, codeHash string, nowUnix int64) (*repo.AuthExchangeRecord, bool, error) {
+	rec := repo.AuthExchangeRecord{CodeHash: codeHash}
+	err := r.DB.QueryRowContext(ctx, `
+DELETE FROM auth_exchanges
+WHERE code_hash=$1
+RETURNING account_id, username, sealed_access_token, expires_at
+`, codeHash).Scan(&rec.AccountID, &rec.Username, &rec.SealedAccessToken, &rec.ExpiresAtUnix)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if rec.ExpiresAtUnix < nowUnix {
+		return nil, false, nil
+	}
+	return &rec, true, nil
+}
+
+func (r *Repo) PutOAuthState(ctx context.Context, rec repo.OAuthStateRecord, nowUnix int64) error {
+	if _, err := r.DB.ExecContext(ctx, `DELETE FROM oauth_states WHERE expires_at < $1`, nowUnix); err != nil {
+		return err
+	}
+	_, err := r.DB.ExecContext(ctx, `
+INSERT INTO oauth_states(state, provider, user_id, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT(state) DO UPDATE SET
+  provider=EXCLUDED.provider,
+  user_id=EXCLUDED.user_id,
+  expires_at=EXCLUDED.expires_at
+`, rec.State, rec.Provider, rec.UserID, rec.ExpiresAtUnix)
+	return err
+}
+
+func (r *Repo) CountOAuthStates(ctx context.Context, nowUnix int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM oauth_states WHERE expires_at >= $1`, nowUnix).Scan(&n)
+	return n, err
+}
+
+func (r *Repo) TakeOAuthState(ctx context.Context, state string, nowUnix int64) (*repo.OAuthStateRecord, bool, error) {
+	rec := repo.OAuthStateRecord{State: state}
+	err := r.DB.QueryRowContext(ctx, `
+DELETE FROM oauth_states
+WHERE state=$1
+RETURNING provider, user_id, expires_at
+`, state).Scan(&rec.Provider, &rec.UserID, &rec.ExpiresAtUnix)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if rec.ExpiresAtUnix < nowUnix {
+		return nil, false, nil
+	}
+	return &rec, true, nil
+}
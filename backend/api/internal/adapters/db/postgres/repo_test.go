@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"io/fs"
+	"regexp"
+	"slices"
+	"testing"
+
+	"gamedivers.de/api/db/migrations"
+)
+
+var (
+	createTableRe     = regexp.MustCompile(`(?is)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)\s*\((.*?)\n\s*\);`)
+	userIDColumnRe    = regexp.MustCompile(`(?im)^\s*user_id\s`)
+	addUserIDColumnRe = regexp.MustCompile(`(?is)ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(\w+)\s+ADD\s+COLUMN\s+(?:IF\s+NOT\s+EXISTS\s+)?user_id\s`)
+)
+
+// DeleteUserData only clears the tables in userOwnedTables, so a migration adding another
+// user_id table must list it there too.
+func TestUserOwnedTablesCoverMigrations(t *testing.T) {
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no embedded migrations found")
+	}
+
+	for _, name := range files {
+		sql, err := fs.ReadFile(migrations.FS, name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+
+		var owned []string
+		for _, m := range createTableRe.FindAllStringSubmatch(string(sql), -1) {
+			if userIDColumnRe.MatchString(m[2]) {
+				owned = append(owned, m[1])
+			}
+		}
+		for _, m := range addUserIDColumnRe.FindAllStringSubmatch(string(sql), -1) {
+			owned = append(owned, m[1])
+		}
+
+		for _, table := range owned {
+			if !slices.Contains(userOwnedTables, table) {
+				t.Errorf("%s: table %s has a user_id column but is missing from userOwnedTables", name, table)
+			}
+		}
+	}
+}
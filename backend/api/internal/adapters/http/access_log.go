@@ -1,11 +1,12 @@
 package httpapi
 
 import (
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
+
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
 )
 
 // requestLogMiddleware intentionally logs path only (without query string).
@@ -16,6 +17,6 @@ func requestLogMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(ww, r)
 
-		log.Printf("%s %s %d %dB %s", r.Method, r.URL.Path, ww.Status(), ww.BytesWritten(), time.Since(start).Round(time.Millisecond))
+		authmw.Logger(r.Context()).Printf("%s %s %d %dB %s", r.Method, r.URL.Path, ww.Status(), ww.BytesWritten(), time.Since(start).Round(time.Millisecond))
 	})
 }
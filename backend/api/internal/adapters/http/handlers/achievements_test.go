@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/adapters/stores/steam"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+// fakeAchievementRepo records stored counts; other methods panic via the nil embed.
+type fakeAchievementRepo struct {
+	repo.Repo
+	saved []repo.UpsertAchievementParams
+}
+
+func (f *fakeAchievementRepo) UpsertUser(context.Context, string, int64) error { return nil }
+
+func (f *fakeAchievementRepo) UpsertAchievementCounts(_ context.Context, p repo.UpsertAchievementParams) error {
+	f.saved = append(f.saved, p)
+	return nil
+}
+
+func serveAchievements(h *SteamHandler, method, path string) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.Get("/v1/steam/library/{appid}/achievements", h.GetAchievements)
+	router.Post("/v1/steam/library/{appid}/achievements/sync", h.SyncAchievements)
+
+	req := httptest.NewRequest(method, path, nil)
+	req = req.WithContext(context.WithValue(req.Context(), authmw.UserContextKey, &authmw.AuthenticatedUser{ID: "user-1"}))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAchievementsPersistOnlyOnSync(t *testing.T) {
+	fake := &fakeAchievementRepo{}
+	h := NewSteamHandler("", "", "https://gamedivers.de", fake, nil)
+	h.achievements = func(_ context.Context, steamID string, appID int) (*steam.AchievementSummary, error) {
+		return &steam.AchievementSummary{AppID: appID, Unlocked: 24, Total: 50}, nil
+	}
+
+	// Looking at a friend's progress must not overwrite the caller's counts.
+	if w := serveAchievements(h, http.MethodGet, "/v1/steam/library/620/achievements?steamid=76561198000000001"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if len(fake.saved) != 0 {
+		t.Fatalf("expected GET to be read-only, stored %+v", fake.saved)
+	}
+
+	w := serveAchievements(h, http.MethodPost, "/v1/steam/library/620/achievements/sync?steamid=76561198000000000")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(fake.saved) != 1 {
+		t.Fatalf("expected one stored summary, got %d", len(fake.saved))
+	}
+	if got := fake.saved[0]; got.UserID != "user-1" || got.ExternalGameID != "620" || got.Unlocked != 24 || got.Total != 50 {
+		t.Fatalf("unexpected stored counts %+v", got)
+	}
+}
+
+func TestAchievementsPrivateProfile(t *testing.T) {
+	fake := &fakeAchievementRepo{}
+	h := NewSteamHandler("", "", "https://gamedivers.de", fake, nil)
+	h.achievements = func(context.Context, string, int) (*steam.AchievementSummary, error) {
+		return nil, steam.ErrSteamProfilePrivate
+	}
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/v1/steam/library/620/achievements?steamid=76561198000000000"},
+		{http.MethodPost, "/v1/steam/library/620/achievements/sync?steamid=76561198000000000"},
+	} {
+		w := serveAchievements(h, tc.method, tc.path)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("%s: expected 403, got %d", tc.method, w.Code)
+		}
+	}
+	if len(fake.saved) != 0 {
+		t.Fatalf("expected nothing stored for a private profile, got %+v", fake.saved)
+	}
+}
+
+func TestAchievementsWithoutSchemaAreEmpty(t *testing.T) {
+	h := NewSteamHandler("", "", "https://gamedivers.de", nil, nil)
+	h.achievements = func(_ context.Context, _ string, appID int) (*steam.AchievementSummary, error) {
+		return &steam.AchievementSummary{AppID: appID, Achievements: []steam.Achievement{}}, nil
+	}
+
+	w := serveAchievements(h, http.MethodGet, "/v1/steam/library/220/achievements?steamid=76561198000000000")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var got steam.AchievementSummary
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Total != 0 || got.Unlocked != 0 || len(got.Achievements) != 0 {
+		t.Fatalf("expected an empty summary, got %+v", got)
+	}
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gamedivers.de/api/internal/adapters/stores/steam"
+	"gamedivers.de/api/internal/jobs"
+	"gamedivers.de/api/internal/migrate"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+// catalogFlushEvery bounds how many CSV rows are buffered before being flushed to the client.
+const catalogFlushEvery = 500
+
+var catalogCSVHeader = []string{
+	"store_id",
+	"external_game_id",
+	"name",
+	"type",
+	"cc",
+	"currency",
+	"current_initial_cents",
+	"current_final_cents",
+	"current_discount_percent",
+	"lowest_final_cents",
+	"fetched_at_unix",
+}
+
+// MigrationRunner reports and applies the embedded database migrations
+type MigrationRunner interface {
+	Status(ctx context.Context) (migrate.Status, error)
+	Run(ctx context.Context) (int, error)
+}
+
+// PriceRefresher reports the background price refresh job's counters
+type PriceRefresher interface {
+	Stats() jobs.UpdaterStats
+}
+
+// AdminHandler serves admin-only endpoints
+type AdminHandler struct {
+	Catalog      repo.CatalogRepo
+	Migrations   MigrationRunner
+	PriceRefresh PriceRefresher
+	// SteamAppDetails reports the Steam appdetails rate-limit breaker. appdetails needs no API key,
+	// so the server always sets it; a nil func only occurs in tests and reports 501.
+	SteamAppDetails func() steam.BreakerState
+}
+
+type appliedMigrationResponse struct {
+	Version   string    `json:"version"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+type migrationStatusResponse struct {
+	Applied []appliedMigrationResponse `json:"applied"`
+	Pending []string                   `json:"pending"`
+}
+
+type migrationRunResponse struct {
+	NewlyApplied int `json:"newlyApplied"`
+	migrationStatusResponse
+}
+
+// ExportCatalogCSV streams all games joined with their stored prices as CSV
+// GET /v1/admin/catalog/export.csv
+func (h *AdminHandler) ExportCatalogCSV(w http.ResponseWriter, r *http.Request) {
+	if h.Catalog == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Catalog export requires persistence")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="catalog.csv"`)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(catalogCSVHeader); err != nil {
+		logSafeError(r.Context(), "catalog export write failed", err)
+		return
+	}
+
+	written := 0
+	err := h.Catalog.ForEachCatalogRow(r.Context(), func(row repo.CatalogRow) error {
+		if err := cw.Write([]string{
+			row.StoreID,
+			row.ExternalGameID,
+			row.Name,
+			row.Type,
+			row.CC,
+			row.Currency,
+			formatOptionalInt(row.InitialCents),
+			formatOptionalInt(row.FinalCents),
+			formatOptionalInt(row.DiscountPercent),
+			formatOptionalInt(row.LowestCents),
+			formatOptionalInt(row.FetchedAtUnix),
+		}); err != nil {
+			return err
+		}
+
+		written++
+		if written%catalogFlushEvery == 0 {
+			cw.Flush()
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			return cw.Error()
+		}
+		return nil
+	})
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	if err != nil {
+		// Headers and part of the body are already sent, so the export can only be cut short.
+		logSafeError(r.Context(), "catalog export failed", err)
+	}
+}
+
+// GetMigrations lists applied and pending schema migrations
+// GET /v1/admin/migrations
+func (h *AdminHandler) GetMigrations(w http.ResponseWriter, r *http.Request) {
+	if h.Migrations == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Migrations require persistence")
+		return
+	}
+
+	status, err := h.Migrations.Status(r.Context())
+	if err != nil {
+		logSafeError(r.Context(), "migration status failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Failed to read migration status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newMigrationStatusResponse(status))
+}
+
+// RunMigrations applies pending schema migrations and reports how many were applied
+// POST /v1/admin/migrations/run
+func (h *AdminHandler) RunMigrations(w http.ResponseWriter, r *http.Request) {
+	if h.Migrations == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Migrations require persistence")
+		return
+	}
+
+	applied, err := h.Migrations.Run(r.Context())
+	if err != nil {
+		logSafeError(r.Context(), "migration run failed", err)
+		writeError(w, http.StatusInternalServerError, "migration_failed", "Failed to apply migrations")
+		return
+	}
+
+	status, err := h.Migrations.Status(r.Context())
+	if err != nil {
+		logSafeError(r.Context(), "migration status failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Failed to read migration status")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(migrationRunResponse{
+		NewlyApplied:            applied,
+		migrationStatusResponse: newMigrationStatusResponse(status),
+	})
+}
+
+func newMigrationStatusResponse(status migrate.Status) migrationStatusResponse {
+	resp := migrationStatusResponse{
+		Applied: make([]appliedMigrationResponse, 0, len(status.Applied)),
+		Pending: status.Pending,
+	}
+	if resp.Pending == nil {
+		resp.Pending = []string{}
+	}
+	for _, m := range status.Applied {
+		resp.Applied = append(resp.Applied, appliedMigrationResponse{Version: m.Version, AppliedAt: m.AppliedAt})
+	}
+	return resp
+}
+
+func formatOptionalInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// GetPriceRefreshStats reports how many stale prices the background job has refreshed
+// GET /v1/admin/price-refresh
+func (h *AdminHandler) GetPriceRefreshStats(w http.ResponseWriter, r *http.Request) {
+	if h.PriceRefresh == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Price refresh requires persistence")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.PriceRefresh.Stats())
+}
+
+// GetSteamAppDetailsBreaker reports whether Steam appdetails calls are paused after repeated 429s
+// GET /v1/admin/steam/appdetails-breaker
+func (h *AdminHandler) GetSteamAppDetailsBreaker(w http.ResponseWriter, r *http.Request) {
+	if h.SteamAppDetails == nil {
+		writeProviderNotConfigured(w, "Steam")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.SteamAppDetails())
+}
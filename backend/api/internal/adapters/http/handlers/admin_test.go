@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gamedivers.de/api/internal/migrate"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+type fakeCatalogRepo struct {
+	rows []repo.CatalogRow
+}
+
+func (f *fakeCatalogRepo) ForEachCatalogRow(_ context.Context, fn func(repo.CatalogRow) error) error {
+	for _, row := range f.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportCatalogCSV(t *testing.T) {
+	final := int64(999)
+	handler := &AdminHandler{Catalog: &fakeCatalogRepo{rows: []repo.CatalogRow{
+		{StoreID: "steam", ExternalGameID: "220", Name: `Half-Life 2: "Episode", One`, Type: "game", CC: "de", Currency: "EUR", FinalCents: &final},
+		{StoreID: "steam", ExternalGameID: "400", Name: "Portal\nStill Alive", Type: "game"},
+		{StoreID: "epic", ExternalGameID: "fn", Name: "Fortnite"},
+	}}}
+
+	req := httptest.NewRequest("GET", "/v1/admin/catalog/export.csv", nil)
+	w := httptest.NewRecorder()
+	handler.ExportCatalogCSV(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("expected text/csv content type, got %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), `"Half-Life 2: ""Episode"", One"`) {
+		t.Fatalf("expected quoted name in output, got:\n%s", w.Body.String())
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected header + 3 rows, got %d records", len(records))
+	}
+	if records[1][2] != `Half-Life 2: "Episode", One` || records[1][7] != "999" {
+		t.Fatalf("unexpected first row %q", records[1])
+	}
+	if records[2][2] != "Portal\nStill Alive" || records[2][7] != "" {
+		t.Fatalf("unexpected second row %q", records[2])
+	}
+}
+
+type fakeMigrationRunner struct {
+	applied []migrate.AppliedMigration
+	pending []string
+	runs    int
+}
+
+func (f *fakeMigrationRunner) Status(context.Context) (migrate.Status, error) {
+	return migrate.Status{Applied: f.applied, Pending: f.pending}, nil
+}
+
+func (f *fakeMigrationRunner) Run(context.Context) (int, error) {
+	f.runs++
+	n := len(f.pending)
+	for _, name := range f.pending {
+		f.applied = append(f.applied, migrate.AppliedMigration{Version: name, AppliedAt: time.Unix(1700000000, 0).UTC()})
+	}
+	f.pending = nil
+	return n, nil
+}
+
+func TestRunMigrationsReportsNewlyApplied(t *testing.T) {
+	runner := &fakeMigrationRunner{
+		applied: []migrate.AppliedMigration{{Version: "init.sql", AppliedAt: time.Unix(1600000000, 0).UTC()}},
+		pending: []string{"watchlist.sql"},
+	}
+	handler := &AdminHandler{Migrations: runner}
+
+	for _, want := range []int{1, 0} {
+		w := httptest.NewRecorder()
+		handler.RunMigrations(w, httptest.NewRequest("POST", "/v1/admin/migrations/run", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var resp migrationRunResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if resp.NewlyApplied != want || len(resp.Applied) != 2 || len(resp.Pending) != 0 {
+			t.Fatalf("unexpected response %+v (want %d newly applied)", resp, want)
+		}
+	}
+}
+
+func TestGetMigrationsWithoutPersistence(t *testing.T) {
+	w := httptest.NewRecorder()
+	(&AdminHandler{}).GetMigrations(w, httptest.NewRequest("GET", "/v1/admin/migrations", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+}
+
+func TestGetSteamAppDetailsBreakerWithoutSteam(t *testing.T) {
+	w := httptest.NewRecorder()
+	(&AdminHandler{}).GetSteamAppDetailsBreaker(w, httptest.NewRequest("GET", "/v1/admin/steam/appdetails-breaker", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("expected status 501, got %d", w.Code)
+	}
+}
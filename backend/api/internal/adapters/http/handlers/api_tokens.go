@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+const maxAPITokenLabelLength = 100
+
+// APITokenHandler manages personal access tokens for the authenticated user
+type APITokenHandler struct {
+	Repo repo.APITokenRepo
+}
+
+// CreateAPITokenRequest represents the token creation request body
+type CreateAPITokenRequest struct {
+	Label  string   `json:"label"`
+	Scopes []string `json:"scopes"`
+}
+
+// CreateAPITokenResponse includes the plaintext token, which is only returned once
+type CreateAPITokenResponse struct {
+	repo.APIToken
+	Token string `json:"token"`
+}
+
+// Create issues a new personal access token
+// POST /v1/auth/tokens
+func (h *APITokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	label := strings.TrimSpace(req.Label)
+	if label == "" || len(label) > maxAPITokenLabelLength {
+		writeError(w, http.StatusBadRequest, "validation_error", "Label is required and must be at most 100 characters")
+		return
+	}
+
+	scopes, ok := normalizeAPITokenScopes(req.Scopes)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "validation_error", "Scopes must be one or more of: "+strings.Join(middleware.APITokenScopes, ", "))
+		return
+	}
+
+	plaintext, err := newStateToken()
+	if err != nil {
+		logSafeError(r.Context(), "api token generation failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Unable to process token request")
+		return
+	}
+	plaintext = middleware.APITokenPrefix + plaintext
+
+	id, err := newAPITokenID()
+	if err != nil {
+		logSafeError(r.Context(), "api token generation failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Unable to process token request")
+		return
+	}
+
+	now := time.Now().Unix()
+	if err := h.Repo.CreateAPIToken(r.Context(), repo.CreateAPITokenParams{
+		ID:            id,
+		UserID:        user.ID,
+		TokenHash:     middleware.HashAPIToken(plaintext),
+		Label:         label,
+		Scopes:        scopes,
+		CreatedAtUnix: now,
+	}); err != nil {
+		logSafeError(r.Context(), "create api token failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Unable to process token request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPITokenResponse{
+		APIToken: repo.APIToken{
+			ID:        id,
+			Label:     label,
+			Scopes:    scopes,
+			CreatedAt: now,
+		},
+		Token: plaintext,
+	})
+}
+
+// List returns the user's personal access tokens without their secrets
+// GET /v1/auth/tokens
+func (h *APITokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.Repo.ListAPITokens(r.Context(), user.ID)
+	if err != nil {
+		logSafeError(r.Context(), "list api tokens failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Unable to process token request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tokens": tokens,
+	})
+}
+
+// Revoke deletes a personal access token
+// DELETE /v1/auth/tokens/{tokenId}
+func (h *APITokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.Repo.RevokeAPIToken(r.Context(), user.ID, chi.URLParam(r, "tokenId"))
+	if err != nil {
+		logSafeError(r.Context(), "revoke api token failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Unable to process token request")
+		return
+	}
+	if !revoked {
+		writeError(w, http.StatusNotFound, "not_found", "Token not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionUser returns the JWT-authenticated user. Token management is never available to personal access tokens.
+func (h *APITokenHandler) sessionUser(w http.ResponseWriter, r *http.Request) (*middleware.AuthenticatedUser, bool) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || user.TokenID != "" {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "User not authenticated")
+		return nil, false
+	}
+	if h.Repo == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "API tokens require persistence")
+		return nil, false
+	}
+	return user, true
+}
+
+func normalizeAPITokenScopes(raw []string) ([]string, bool) {
+	seen := make(map[string]struct{}, len(raw))
+	scopes := make([]string, 0, len(raw))
+	for _, scope := range raw {
+		scope = strings.TrimSpace(scope)
+		if !middleware.IsValidAPITokenScope(scope) {
+			return nil, false
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+	return scopes, len(scopes) > 0
+}
+
+func newAPITokenID() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
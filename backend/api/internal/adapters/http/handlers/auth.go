@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -13,8 +14,9 @@ import (
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	Keycloak *keycloak.Client
-	Repo     repo.UserRepo
+	Keycloak       *keycloak.Client
+	Repo           repo.UserRepo
+	PasswordPolicy PasswordPolicy
 }
 
 // RegisterRequest represents the registration request body
@@ -57,6 +59,7 @@ type UserResponse struct {
 	Email                     string `json:"email"`
 	FirstName                 string `json:"firstName,omitempty"`
 	LastName                  string `json:"lastName,omitempty"`
+	Country                   string `json:"country,omitempty"`
 	VerificationEmailRequired *bool  `json:"verificationEmailRequired,omitempty"`
 	VerificationEmailSent     *bool  `json:"verificationEmailSent,omitempty"`
 	Warning                   string `json:"warning,omitempty"`
@@ -77,23 +80,8 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if req.Username == "" {
-		writeError(w, http.StatusBadRequest, "validation_error", "Username is required")
-		return
-	}
-	if req.Email == "" {
-		writeError(w, http.StatusBadRequest, "validation_error", "Email is required")
-		return
-	}
-	if req.Password == "" {
-		writeError(w, http.StatusBadRequest, "validation_error", "Password is required")
-		return
-	}
-
-	// Validate password strength (minimum 8 characters)
-	if len(req.Password) < 8 {
-		writeError(w, http.StatusBadRequest, "validation_error", "Password must be at least 8 characters")
+	if errs := validateRegistration(req, h.PasswordPolicy); len(errs) > 0 {
+		writeValidationErrors(w, http.StatusBadRequest, "validation_error", "Registration details are invalid", errs)
 		return
 	}
 
@@ -106,11 +94,18 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		LastName:  req.LastName,
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
+		switch {
+		case errors.Is(err, keycloak.ErrUsernameTaken):
+			writeValidationErrors(w, http.StatusConflict, "user_exists", "User already exists", map[string]string{"username": "is already taken"})
+			return
+		case errors.Is(err, keycloak.ErrEmailTaken):
+			writeValidationErrors(w, http.StatusConflict, "user_exists", "User already exists", map[string]string{"email": "is already registered"})
+			return
+		case errors.Is(err, keycloak.ErrUserExists):
 			writeError(w, http.StatusConflict, "user_exists", "User already exists")
 			return
 		}
-		logSafeError("register upstream auth failed", err)
+		logSafeError(r.Context(), "register upstream auth failed", err)
 		writeError(w, http.StatusBadGateway, "keycloak_error", "Authentication service unavailable")
 		return
 	}
@@ -170,7 +165,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusUnauthorized, "invalid_credentials", "Invalid username or password")
 			return
 		}
-		logSafeError("login upstream auth failed", err)
+		logSafeError(r.Context(), "login upstream auth failed", err)
 		writeError(w, http.StatusBadGateway, "keycloak_error", "Authentication service unavailable")
 		return
 	}
@@ -244,6 +239,16 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	country := ""
+	if h.Repo != nil {
+		stored, err := h.Repo.GetUser(r.Context(), user.ID)
+		if err != nil {
+			logSafeError(r.Context(), "load user profile failed", err)
+		} else if stored != nil {
+			country = stored.Country
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(UserResponse{
 		ID:        user.ID,
@@ -251,6 +256,7 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 		Email:     user.Email,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
+		Country:   country,
 	})
 }
 
@@ -260,6 +266,11 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"newPassword"`
 }
 
+// DeleteAccountRequest confirms account deletion with the user's password
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
 // UpdateProfileRequest represents the profile update request body
 type UpdateProfileRequest struct {
 	Email     string `json:"email,omitempty"`
@@ -290,18 +301,65 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "validation_error", "New password is required")
 		return
 	}
-	if len(req.NewPassword) < 8 {
-		writeError(w, http.StatusBadRequest, "validation_error", "New password must be at least 8 characters")
+	if msg := h.PasswordPolicy.check(req.NewPassword); msg != "" {
+		writeValidationErrors(w, http.StatusBadRequest, "validation_error", "New password "+msg, map[string]string{"newPassword": msg})
 		return
 	}
 
 	err := h.Keycloak.ChangePassword(r.Context(), user.ID, req.CurrentPassword, req.NewPassword)
 	if err != nil {
-		if strings.Contains(err.Error(), "incorrect") {
+		if errors.Is(err, keycloak.ErrIncorrectPassword) {
 			writeError(w, http.StatusBadRequest, "invalid_password", "Current password is incorrect")
 			return
 		}
-		logSafeError("change password upstream auth failed", err)
+		logSafeError(r.Context(), "change password upstream auth failed", err)
+		writeError(w, http.StatusBadGateway, "keycloak_error", "Authentication service unavailable")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteAccount permanently deletes the authenticated user's data and Keycloak account
+// DELETE /v1/auth/account
+func (h *AuthHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "User not authenticated")
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	if req.Password == "" {
+		writeValidationErrors(w, http.StatusBadRequest, "validation_error", "Password confirmation is required", map[string]string{"password": "is required"})
+		return
+	}
+
+	if err := h.Keycloak.VerifyPassword(r.Context(), user.ID, req.Password); err != nil {
+		if errors.Is(err, keycloak.ErrIncorrectPassword) {
+			writeError(w, http.StatusBadRequest, "invalid_password", "Password is incorrect")
+			return
+		}
+		logSafeError(r.Context(), "delete account password check failed", err)
+		writeError(w, http.StatusBadGateway, "keycloak_error", "Authentication service unavailable")
+		return
+	}
+
+	// Local data goes first: if Keycloak then fails, the user can still log in and retry.
+	if h.Repo != nil {
+		if err := h.Repo.DeleteUserData(r.Context(), user.ID); err != nil {
+			logSafeError(r.Context(), "delete account data failed", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "Failed to delete account data")
+			return
+		}
+	}
+
+	if err := h.Keycloak.DeleteUser(r.Context(), user.ID); err != nil && !errors.Is(err, keycloak.ErrUserNotFound) {
+		logSafeError(r.Context(), "delete account upstream auth failed", err)
 		writeError(w, http.StatusBadGateway, "keycloak_error", "Authentication service unavailable")
 		return
 	}
@@ -336,7 +394,7 @@ func (h *AuthHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		LastName:  req.LastName,
 	})
 	if err != nil {
-		logSafeError("update profile upstream auth failed", err)
+		logSafeError(r.Context(), "update profile upstream auth failed", err)
 		writeError(w, http.StatusBadGateway, "keycloak_error", "Authentication service unavailable")
 		return
 	}
@@ -351,6 +409,49 @@ func (h *AuthHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateRegionRequest represents the preferred region update request body
+type UpdateRegionRequest struct {
+	Country string `json:"country"`
+}
+
+// UpdateRegion stores the user's preferred price region
+// PUT /v1/auth/region
+func (h *AuthHandler) UpdateRegion(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "User not authenticated")
+		return
+	}
+
+	var req UpdateRegionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	country, ok := normalizeCountry(req.Country)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "validation_error", "Country must be an ISO 3166-1 alpha-2 code")
+		return
+	}
+
+	if h.Repo == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Region preference requires persistence")
+		return
+	}
+
+	if err := h.Repo.SetUserCountry(r.Context(), user.ID, country, time.Now().Unix()); err != nil {
+		logSafeError(r.Context(), "set user region failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "Unable to save region")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"country": country,
+	})
+}
+
 // ForgotPasswordRequest represents the forgot password request body
 type ForgotPasswordRequest struct {
 	Email string `json:"email"`
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gamedivers.de/api/internal/adapters/auth/keycloak"
+	"gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+type fakeAccountRepo struct {
+	repo.UserRepo
+	deleted []string
+}
+
+func (f *fakeAccountRepo) DeleteUserData(_ context.Context, userID string) error {
+	f.deleted = append(f.deleted, userID)
+	return nil
+}
+
+// newAccountKeycloak fakes the realm endpoints DeleteAccount uses; the user's password is "correct-horse".
+func newAccountKeycloak(t *testing.T, kcDeleted *[]string) *keycloak.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token"):
+			_ = r.ParseForm()
+			if r.PostForm.Get("grant_type") == "password" && r.PostForm.Get("password") != "correct-horse" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid user credentials"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"token","expires_in":60,"token_type":"Bearer"}`))
+		case strings.HasSuffix(r.URL.Path, "/users/user-1") && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":"user-1","username":"player"}`))
+		case strings.HasSuffix(r.URL.Path, "/users/user-1") && r.Method == http.MethodDelete:
+			*kcDeleted = append(*kcDeleted, "user-1")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return keycloak.NewClient(server.URL, "demo", "api", "secret", true)
+}
+
+func deleteAccountRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodDelete, "/v1/auth/account", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), middleware.UserContextKey, &middleware.AuthenticatedUser{ID: "user-1"})
+	return req.WithContext(ctx)
+}
+
+func TestDeleteAccountRemovesDataAndKeycloakUser(t *testing.T) {
+	var kcDeleted []string
+	store := &fakeAccountRepo{}
+	h := &AuthHandler{Keycloak: newAccountKeycloak(t, &kcDeleted), Repo: store}
+
+	w := httptest.NewRecorder()
+	h.DeleteAccount(w, deleteAccountRequest(`{"password":"correct-horse"}`))
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != "user-1" {
+		t.Fatalf("expected user-1 data deleted, got %v", store.deleted)
+	}
+	if len(kcDeleted) != 1 {
+		t.Fatalf("expected Keycloak user deleted, got %v", kcDeleted)
+	}
+}
+
+func TestDeleteAccountRequiresCorrectPassword(t *testing.T) {
+	var kcDeleted []string
+	store := &fakeAccountRepo{}
+	h := &AuthHandler{Keycloak: newAccountKeycloak(t, &kcDeleted), Repo: store}
+
+	for body, wantCode := range map[string]string{
+		`{"password":"wrong"}`: "invalid_password",
+		`{}`:                   "validation_error",
+	} {
+		w := httptest.NewRecorder()
+		h.DeleteAccount(w, deleteAccountRequest(body))
+
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), wantCode) {
+			t.Fatalf("body %s: expected 400 %s, got %d: %s", body, wantCode, w.Code, w.Body.String())
+		}
+	}
+	if len(store.deleted) != 0 || len(kcDeleted) != 0 {
+		t.Fatalf("nothing may be deleted without confirmation, got data=%v keycloak=%v", store.deleted, kcDeleted)
+	}
+}
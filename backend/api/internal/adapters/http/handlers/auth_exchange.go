@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+// authExchangeTTL bounds how long a callback's one-time code can be redeemed.
+const authExchangeTTL = 2 * time.Minute
+
+// AuthExchange is what a one-time exchange code redeems to.
+type AuthExchange struct {
+	AccountID   string `json:"accountId"`
+	Username    string `json:"username"`
+	AccessToken string `json:"accessToken"`
+	expiresAt   time.Time
+}
+
+// ExchangeStore holds provider tokens behind short-lived single-use codes,
+// so OAuth callbacks never put the token itself in a redirect URL.
+type ExchangeStore interface {
+	// Issue stores entry and returns the code that redeems it.
+	Issue(ctx context.Context, entry AuthExchange) (string, error)
+	// Redeem returns and deletes the entry for code. Expired, unknown and already redeemed codes report false.
+	Redeem(ctx context.Context, code string) (AuthExchange, bool, error)
+}
+
+// MemoryExchangeStore is an in-process ExchangeStore. The callback and the redeem request must
+// reach the same process, so it only suits single-replica deployments; see RepoExchangeStore.
+type MemoryExchangeStore struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]AuthExchange
+}
+
+func NewMemoryExchangeStore(ttl time.Duration) *MemoryExchangeStore {
+	if ttl <= 0 {
+		ttl = authExchangeTTL
+	}
+	return &MemoryExchangeStore{
+		ttl:        ttl,
+		maxEntries: defaultOAuthStateMaxEntries,
+		now:        time.Now,
+		entries:    make(map[string]AuthExchange),
+	}
+}
+
+func (s *MemoryExchangeStore) Issue(_ context.Context, entry AuthExchange) (string, error) {
+	code, err := newStateToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, existing := range s.entries {
+		if now.After(existing.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	if len(s.entries) >= s.maxEntries {
+		return "", ErrOAuthStateStoreFull
+	}
+
+	entry.expiresAt = now.Add(s.ttl)
+	s.entries[code] = entry
+	return code, nil
+}
+
+func (s *MemoryExchangeStore) Redeem(_ context.Context, code string) (AuthExchange, bool, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[code]
+	delete(s.entries, code)
+	s.mu.Unlock()
+
+	if !ok || s.now().After(entry.expiresAt) {
+		return AuthExchange{}, false, nil
+	}
+	return entry, true, nil
+}
+
+// RepoExchangeStore keeps exchange codes in the database, so the callback and the redeem
+// request may be served by different API replicas. Only a hash of each code is stored, and the
+// provider token is encrypted with a key derived from the code, so a database read or a backup
+// does not yield live tokens.
+type RepoExchangeStore struct {
+	repo repo.AuthExchangeRepo
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewRepoExchangeStore(r repo.AuthExchangeRepo, ttl time.Duration) *RepoExchangeStore {
+	if ttl <= 0 {
+		ttl = authExchangeTTL
+	}
+	return &RepoExchangeStore{repo: r, ttl: ttl, now: time.Now}
+}
+
+func (s *RepoExchangeStore) Issue(ctx context.Context, entry AuthExchange) (string, error) {
+	code, err := newStateToken()
+	if err != nil {
+		return "", err
+	}
+
+	codeHash := hashExchangeCode(code)
+	sealed, err := sealExchangeToken(code, codeHash, entry.AccessToken)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+	err = s.repo.PutAuthExchange(ctx, repo.AuthExchangeRecord{
+		CodeHash:          codeHash,
+		AccountID:         entry.AccountID,
+		Username:          entry.Username,
+		SealedAccessToken: sealed,
+		ExpiresAtUnix:     now.Add(s.ttl).Unix(),
+	}, now.Unix())
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+func (s *RepoExchangeStore) Redeem(ctx context.Context, code string) (AuthExchange, bool, error) {
+	codeHash := hashExchangeCode(code)
+	rec, ok, err := s.repo.TakeAuthExchange(ctx, codeHash, s.now().Unix())
+	if err != nil || !ok {
+		return AuthExchange{}, false, err
+	}
+	token, err := openExchangeToken(code, codeHash, rec.SealedAccessToken)
+	if err != nil {
+		// Not decryptable with this code, e.g. a row written before tokens were sealed.
+		return AuthExchange{}, false, nil
+	}
+	return AuthExchange{AccountID: rec.AccountID, Username: rec.Username, AccessToken: token}, true, nil
+}
+
+func hashExchangeCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// exchangeTokenCipher derives the AES-256-GCM key for a code's token. The derivation differs
+// from hashExchangeCode, so the stored hash does not reveal the key.
+func exchangeTokenCipher(code string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("auth-exchange-token\x00" + code))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealExchangeToken encrypts token for code, bound to its stored hash, as base64(nonce || ciphertext).
+func sealExchangeToken(code, codeHash, token string) (string, error) {
+	aead, err := exchangeTokenCipher(code)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(token), []byte(codeHash))), nil
+}
+
+func openExchangeToken(code, codeHash, sealed string) (string, error) {
+	aead, err := exchangeTokenCipher(code)
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < aead.NonceSize() {
+		return "", errors.New("sealed token too short")
+	}
+	token, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(codeHash))
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+type authExchangeRequest struct {
+	Code string `json:"code"`
+}
+
+// redeemExchange answers POST {"code": "..."} with the stored login, once.
+func redeemExchange(w http.ResponseWriter, r *http.Request, exchanges ExchangeStore) {
+	var req authExchangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "code is required")
+		return
+	}
+
+	entry, ok, err := exchanges.Redeem(r.Context(), req.Code)
+	if err != nil {
+		logSafeError(r.Context(), "auth exchange redeem failed", err)
+		writeInternalError(w)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_code", "Exchange code is invalid, expired or already used")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(entry)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+func redeemRequest(t *testing.T, h *EpicHandler, code string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.Exchange(w, httptest.NewRequest(http.MethodPost, "/v1/epic/exchange", strings.NewReader(`{"code":"`+code+`"}`)))
+	return w
+}
+
+func TestEpicExchangeIsSingleUse(t *testing.T) {
+	h := NewEpicHandler("", "", "", "https://gamedivers.de", nil)
+	code, err := h.exchanges.Issue(context.Background(), AuthExchange{AccountID: "acc-1", Username: "Player", AccessToken: "epic-token"})
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+
+	w := redeemRequest(t, h, code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected no-store, got %q", w.Header().Get("Cache-Control"))
+	}
+	var got AuthExchange
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.AccountID != "acc-1" || got.Username != "Player" || got.AccessToken != "epic-token" {
+		t.Fatalf("unexpected exchange %+v", got)
+	}
+
+	if w := redeemRequest(t, h, code); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_code") {
+		t.Fatalf("expected replayed code to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestEpicExchangeExpires(t *testing.T) {
+	h := NewEpicHandler("", "", "", "https://gamedivers.de", nil)
+	store := NewMemoryExchangeStore(authExchangeTTL)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	h.SetExchangeStore(store)
+
+	code, err := store.Issue(context.Background(), AuthExchange{AccountID: "acc-1", AccessToken: "epic-token"})
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+
+	now = now.Add(authExchangeTTL + time.Second)
+	if w := redeemRequest(t, h, code); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected expired code to be rejected, got %d", w.Code)
+	}
+}
+
+func TestEpicExchangeRequiresCode(t *testing.T) {
+	h := NewEpicHandler("", "", "", "https://gamedivers.de", nil)
+	if w := redeemRequest(t, h, ""); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "invalid_request") {
+		t.Fatalf("expected missing code to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// fakeExchangeRepo stands in for the shared database behind several API replicas.
+type fakeExchangeRepo struct {
+	repo.AuthExchangeRepo
+	records map[string]repo.AuthExchangeRecord
+}
+
+func (f *fakeExchangeRepo) PutAuthExchange(_ context.Context, rec repo.AuthExchangeRecord, _ int64) error {
+	f.records[rec.CodeHash] = rec
+	return nil
+}
+
+func (f *fakeExchangeRepo) TakeAuthExchange(_ context.Context, codeHash string, nowUnix int64) (*repo.AuthExchangeRecord, bool, error) {
+	rec, ok := f.records[codeHash]
+	delete(f.records, codeHash)
+	if !ok || rec.ExpiresAtUnix < nowUnix {
+		return nil, false, nil
+	}
+	return &rec, true, nil
+}
+
+func TestRepoExchangeStoreRedeemsOnAnotherReplica(t *testing.T) {
+	shared := &fakeExchangeRepo{records: map[string]repo.AuthExchangeRecord{}}
+	callbackPod := NewEpicHandler("", "", "", "https://gamedivers.de", nil)
+	callbackPod.SetExchangeStore(NewRepoExchangeStore(shared, 0))
+	redeemPod := NewEpicHandler("", "", "", "https://gamedivers.de", nil)
+	redeemPod.SetExchangeStore(NewRepoExchangeStore(shared, 0))
+
+	code, err := callbackPod.exchanges.Issue(context.Background(), AuthExchange{AccountID: "acc-1", Username: "Player", AccessToken: "epic-token"})
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+	if _, stored := shared.records[code]; stored {
+		t.Fatal("expected only a hash of the code to be stored")
+	}
+	for _, rec := range shared.records {
+		if strings.Contains(rec.SealedAccessToken, "epic-token") || rec.SealedAccessToken == "" {
+			t.Fatalf("expected the access token to be encrypted at rest, got %q", rec.SealedAccessToken)
+		}
+	}
+
+	w := redeemRequest(t, redeemPod, code)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "epic-token") {
+		t.Fatalf("expected the other replica to redeem the code, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := redeemRequest(t, callbackPod, code); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected the code to be single-use across replicas, got %d", w.Code)
+	}
+}
+
+func TestRepoExchangeStoreTokenNeedsTheCode(t *testing.T) {
+	code, err := newStateToken()
+	if err != nil {
+		t.Fatalf("code: %v", err)
+	}
+	sealed, err := sealExchangeToken(code, hashExchangeCode(code), "epic-token")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	if got, err := openExchangeToken(code, hashExchangeCode(code), sealed); err != nil || got != "epic-token" {
+		t.Fatalf("expected the code to open the token, got %q (%v)", got, err)
+	}
+	other, _ := newStateToken()
+	if _, err := openExchangeToken(other, hashExchangeCode(code), sealed); err == nil {
+		t.Fatal("expected another code not to open the token")
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultPasswordMinLength applies when PasswordPolicy.MinLength is unset.
+const defaultPasswordMinLength = 8
+
+// PasswordPolicy configures what passwords registration and password changes accept.
+type PasswordPolicy struct {
+	MinLength int
+	// RequireMix requires upper-case, lower-case and digit characters.
+	RequireMix bool
+}
+
+// ValidationErrorResponse is an ErrorResponse with per-field messages.
+type ValidationErrorResponse struct {
+	ErrorResponse
+	Errors map[string]string `json:"errors"`
+}
+
+// check returns a message describing why password violates the policy, or "".
+func (p PasswordPolicy) check(password string) string {
+	minLength := p.MinLength
+	if minLength <= 0 {
+		minLength = defaultPasswordMinLength
+	}
+	if len([]rune(password)) < minLength {
+		return "must be at least " + strconv.Itoa(minLength) + " characters"
+	}
+
+	if p.RequireMix {
+		var upper, lower, digit bool
+		for _, r := range password {
+			switch {
+			case unicode.IsUpper(r):
+				upper = true
+			case unicode.IsLower(r):
+				lower = true
+			case unicode.IsDigit(r):
+				digit = true
+			}
+		}
+		if !upper || !lower || !digit {
+			return "must contain upper-case and lower-case letters and a digit"
+		}
+	}
+	return ""
+}
+
+// validateRegistration returns a message per invalid field; an empty map means the request is valid.
+func validateRegistration(req RegisterRequest, policy PasswordPolicy) map[string]string {
+	errs := map[string]string{}
+
+	switch {
+	case strings.TrimSpace(req.Username) == "":
+		errs["username"] = "is required"
+	case strings.ContainsFunc(req.Username, unicode.IsSpace):
+		errs["username"] = "must not contain spaces"
+	}
+
+	if req.Email == "" {
+		errs["email"] = "is required"
+	} else if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email {
+		errs["email"] = "must be a valid email address"
+	}
+
+	if req.Password == "" {
+		errs["password"] = "is required"
+	} else if msg := policy.check(req.Password); msg != "" {
+		errs["password"] = msg
+	}
+
+	return errs
+}
+
+func writeValidationErrors(w http.ResponseWriter, status int, code, message string, errs map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ValidationErrorResponse{
+		ErrorResponse: ErrorResponse{Error: code, Message: message},
+		Errors:        errs,
+	})
+}
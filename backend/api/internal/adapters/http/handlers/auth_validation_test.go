@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gamedivers.de/api/internal/adapters/auth/keycloak"
+)
+
+func TestValidateRegistration(t *testing.T) {
+	valid := RegisterRequest{Username: "player", Email: "player@example.com", Password: "Correct1horse"}
+
+	tests := []struct {
+		name   string
+		mutate func(*RegisterRequest)
+		policy PasswordPolicy
+		field  string
+		want   string
+	}{
+		{"missing username", func(r *RegisterRequest) { r.Username = " " }, PasswordPolicy{}, "username", "is required"},
+		{"username with spaces", func(r *RegisterRequest) { r.Username = "two words" }, PasswordPolicy{}, "username", "must not contain spaces"},
+		{"missing email", func(r *RegisterRequest) { r.Email = "" }, PasswordPolicy{}, "email", "is required"},
+		{"malformed email", func(r *RegisterRequest) { r.Email = "not-an-email" }, PasswordPolicy{}, "email", "must be a valid email address"},
+		{"display-name email", func(r *RegisterRequest) { r.Email = "Player <player@example.com>" }, PasswordPolicy{}, "email", "must be a valid email address"},
+		{"missing password", func(r *RegisterRequest) { r.Password = "" }, PasswordPolicy{}, "password", "is required"},
+		{"short password", func(r *RegisterRequest) { r.Password = "short" }, PasswordPolicy{}, "password", "must be at least 8 characters"},
+		{"custom min length", func(r *RegisterRequest) { r.Password = "Correct1" }, PasswordPolicy{MinLength: 12}, "password", "must be at least 12 characters"},
+		{"no mix", func(r *RegisterRequest) { r.Password = "correcthorse" }, PasswordPolicy{RequireMix: true}, "password", "must contain upper-case and lower-case letters and a digit"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			tc.mutate(&req)
+			errs := validateRegistration(req, tc.policy)
+			if len(errs) != 1 || errs[tc.field] != tc.want {
+				t.Fatalf("expected %s %q, got %v", tc.field, tc.want, errs)
+			}
+		})
+	}
+
+	if errs := validateRegistration(valid, PasswordPolicy{RequireMix: true}); len(errs) != 0 {
+		t.Fatalf("expected valid request, got %v", errs)
+	}
+}
+
+func TestRegisterReturnsFieldErrors(t *testing.T) {
+	h := &AuthHandler{}
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(`{"username":"","email":"x","password":"short"}`))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var body ValidationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != "validation_error" || len(body.Errors) != 3 || body.Errors["password"] != "must be at least 8 characters" {
+		t.Fatalf("unexpected body %+v", body)
+	}
+}
+
+func TestRegisterUsernameConflict(t *testing.T) {
+	kc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token") {
+			_, _ = w.Write([]byte(`{"access_token":"admin-token","expires_in":60,"token_type":"Bearer"}`))
+			return
+		}
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"errorMessage":"User exists with same username"}`))
+	}))
+	defer kc.Close()
+
+	h := &AuthHandler{Keycloak: keycloak.NewClient(kc.URL, "demo", "api", "secret", true)}
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(`{"username":"player","email":"player@example.com","password":"correct-horse"}`))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+	var body ValidationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != "user_exists" || body.Errors["username"] != "is already taken" {
+		t.Fatalf("unexpected body %+v", body)
+	}
+}
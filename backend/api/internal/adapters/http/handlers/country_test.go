@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+type fakeCountryUsers struct {
+	repo.UserRepo
+	country string
+}
+
+func (f *fakeCountryUsers) GetUser(_ context.Context, userID string) (*repo.User, error) {
+	return &repo.User{ID: userID, Country: f.country}, nil
+}
+
+func TestRequestCountryUsesStoredUserCountry(t *testing.T) {
+	users := &fakeCountryUsers{country: "fr"}
+	req := httptest.NewRequest("GET", "/v1/steam/wishlist?steamid=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, &middleware.AuthenticatedUser{ID: "user-1"}))
+
+	if got, ok := requestCountry(req, "", users, "US"); !ok || got != "FR" {
+		t.Fatalf("expected the stored country FR, got %q (ok=%t)", got, ok)
+	}
+	if got, ok := requestCountry(req, "gb", users, "US"); !ok || got != "GB" {
+		t.Fatalf("expected the explicit country GB, got %q (ok=%t)", got, ok)
+	}
+
+	users.country = ""
+	if got, ok := requestCountry(req, "", users, "US"); !ok || got != "US" {
+		t.Fatalf("expected the default country US, got %q (ok=%t)", got, ok)
+	}
+	if _, ok := requestCountry(req, "xx", users, "US"); ok {
+		t.Fatal("expected an invalid explicit country to be rejected")
+	}
+}
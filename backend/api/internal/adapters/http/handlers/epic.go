@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"crypto/rand"
-	"crypto/subtle"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -11,11 +9,17 @@ import (
 	"strings"
 
 	"gamedivers.de/api/internal/adapters/stores/epic"
+	"gamedivers.de/api/internal/ports/store"
 	"github.com/go-chi/chi/v5"
 )
 
 type EpicHandler struct {
 	client *epic.Client
+	// exchangeCode trades an authorization code for tokens (client.ExchangeCode outside tests)
+	exchangeCode func(ctx context.Context, code string) (*epic.OAuthTokenResponse, error)
+	states       StateStore
+	// exchanges holds access tokens behind the one-time codes handed to the frontend
+	exchanges ExchangeStore
 	// allowedRedirect is the single frontend origin we accept
 	allowedRedirect string
 }
@@ -29,27 +33,45 @@ type EpicGameResponse struct {
 	Namespace string `json:"namespace"`
 }
 
-func NewEpicHandler(clientID, clientSecret, redirectURI, frontendOrigin string) *EpicHandler {
+func NewEpicHandler(clientID, clientSecret, redirectURI, frontendOrigin string, states StateStore) *EpicHandler {
+	if states == nil {
+		states = NewMemoryStateStore(defaultOAuthStateTTL)
+	}
+	client := epic.NewClient(clientID, clientSecret, redirectURI)
 	return &EpicHandler{
-		client:          epic.NewClient(clientID, clientSecret, redirectURI),
+		client:          client,
+		exchangeCode:    client.ExchangeCode,
+		states:          states,
+		exchanges:       NewMemoryExchangeStore(authExchangeTTL),
 		allowedRedirect: frontendOrigin,
 	}
 }
 
+// SetExchangeStore replaces the in-process exchange code store, e.g. with a RepoExchangeStore
+// when the API runs with more than one replica.
+func (h *EpicHandler) SetExchangeStore(store ExchangeStore) {
+	if store != nil {
+		h.exchanges = store
+	}
+}
+
+// SetRateLimit configures the Epic API request rate (requests per second) and burst.
+func (h *EpicHandler) SetRateLimit(perSecond float64, burst int) {
+	h.client.SetRateLimit(perSecond, burst)
+}
+
 func (h *EpicHandler) LoginRedirect(w http.ResponseWriter, r *http.Request) {
-	state, err := newEpicStateToken()
+	if !h.client.Configured() {
+		writeProviderNotConfigured(w, "Epic Games")
+		return
+	}
+
+	state, err := issueOAuthState(r, h.states, "epic")
 	if err != nil {
+		logSafeError(r.Context(), "epic state generation failed", err)
 		http.Error(w, "state generation failed", http.StatusInternalServerError)
 		return
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     "epic_state",
-		Value:    state,
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Secure:   isSecureRequest(r),
-	})
 
 	loginURL := h.client.GetLoginURL(state)
 
@@ -65,30 +87,37 @@ func (h *EpicHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.verifyState(r, state); err != nil {
-		http.Error(w, "invalid state", http.StatusBadRequest)
+	entry, err := consumeOAuthState(r, h.states, "epic", state)
+	if err != nil {
+		writeOAuthStateError(w, err)
 		return
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     "epic_state",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Secure:   isSecureRequest(r),
-		MaxAge:   -1,
-	})
 
-	tokenResp, err := h.client.ExchangeCode(r.Context(), code)
+	tokenResp, err := h.exchangeCode(r.Context(), code)
 	if err != nil {
-		logSafeError("epic token exchange failed", err)
+		if consentErr, ok := epic.IsScopeConsentRequired(err); ok && isEpicContinuationURL(consentErr.ContinuationURL) {
+			// Epic wants the user to approve the requested scopes first; it redirects back with a new
+			// code and the same state, so put the state back for that second callback.
+			if err := h.states.Put(r.Context(), state, entry); err != nil {
+				logSafeError(r.Context(), "epic state re-issue failed", err)
+				http.Error(w, "state generation failed", http.StatusInternalServerError)
+				return
+			}
+			http.Redirect(w, r, consentErr.ContinuationURL, http.StatusTemporaryRedirect)
+			return
+		}
+		logSafeError(r.Context(), "epic token exchange failed", err)
+		if errors.Is(err, store.ErrProviderNotConfigured) {
+			writeProviderNotConfigured(w, "Epic Games")
+			return
+		}
 		http.Error(w, "authentication failed", http.StatusInternalServerError)
 		return
 	}
 
 	accountInfo, err := h.client.GetAccountInfo(r.Context(), tokenResp.AccessToken)
 	if err != nil {
-		logSafeError("epic account info fetch failed", err)
+		logSafeError(r.Context(), "epic account info fetch failed", err)
 		http.Error(w, "failed to get account info", http.StatusInternalServerError)
 		return
 	}
@@ -106,10 +135,22 @@ func (h *EpicHandler) Callback(w http.ResponseWriter, r *http.Request) {
 	if allowedRedirect == "" {
 		allowedRedirect = redirectBase
 	}
+	// The token stays server-side; the frontend trades this code for it via POST /v1/epic/exchange.
+	exchangeCode, err := h.exchanges.Issue(r.Context(), AuthExchange{
+		AccountID:   tokenResp.AccountID,
+		Username:    displayName,
+		AccessToken: tokenResp.AccessToken,
+	})
+	if err != nil {
+		logSafeError(r.Context(), "epic exchange code issue failed", err)
+		http.Error(w, "authentication failed", http.StatusInternalServerError)
+		return
+	}
+
 	redirectURL, err := safeRedirect(redirectBase, allowedRedirect, map[string]string{
-		"epicid":       tokenResp.AccountID,
-		"username":     displayName,
-		"access_token": tokenResp.AccessToken,
+		"epicid":   tokenResp.AccountID,
+		"username": displayName,
+		"code":     exchangeCode,
 	})
 	if err != nil {
 		http.Error(w, "invalid redirect", http.StatusBadRequest)
@@ -119,6 +160,12 @@ func (h *EpicHandler) Callback(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
 }
 
+// Exchange redeems the one-time code from the login callback for the Epic access token
+// POST /v1/epic/exchange
+func (h *EpicHandler) Exchange(w http.ResponseWriter, r *http.Request) {
+	redeemExchange(w, r, h.exchanges)
+}
+
 func (h *EpicHandler) GetLibrary(w http.ResponseWriter, r *http.Request) {
 	accessToken := extractBearerToken(r)
 	if accessToken == "" {
@@ -128,7 +175,7 @@ func (h *EpicHandler) GetLibrary(w http.ResponseWriter, r *http.Request) {
 
 	games, err := h.client.GetLibrary(r.Context(), accessToken)
 	if err != nil {
-		logSafeError("epic library fetch failed", err)
+		logSafeError(r.Context(), "epic library fetch failed", err)
 		http.Error(w, "failed to fetch library", http.StatusInternalServerError)
 		return
 	}
@@ -158,7 +205,7 @@ func (h *EpicHandler) SyncLibrary(w http.ResponseWriter, r *http.Request) {
 
 	games, err := h.client.GetLibrary(r.Context(), accessToken)
 	if err != nil {
-		logSafeError("epic sync failed", err)
+		logSafeError(r.Context(), "epic sync failed", err)
 		http.Error(w, "sync failed", http.StatusInternalServerError)
 		return
 	}
@@ -176,6 +223,7 @@ func (h *EpicHandler) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/login", h.LoginRedirect)
 	r.Get("/callback", h.Callback)
+	r.Post("/exchange", h.Exchange)
 	r.Get("/library", h.GetLibrary)
 	r.Post("/sync", h.SyncLibrary)
 	return r
@@ -183,28 +231,6 @@ func (h *EpicHandler) Routes() chi.Router {
 
 // --- helpers ---
 
-func newEpicStateToken() (string, error) {
-	var buf [32]byte
-	if _, err := rand.Read(buf[:]); err != nil {
-		return "", err
-	}
-	return base64.RawURLEncoding.EncodeToString(buf[:]), nil
-}
-
-func (h *EpicHandler) verifyState(r *http.Request, state string) error {
-	if state == "" {
-		return errors.New("empty state")
-	}
-	cookie, err := r.Cookie("epic_state")
-	if err != nil {
-		return err
-	}
-	if subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
-		return errors.New("state mismatch")
-	}
-	return nil
-}
-
 func safeRedirect(base, allowedBase string, params map[string]string) (string, error) {
 	u, err := url.Parse(base)
 	if err != nil {
@@ -250,3 +276,13 @@ func extractBearerToken(r *http.Request) string {
 	}
 	return strings.TrimSpace(parts[1])
 }
+
+// isEpicContinuationURL only allows redirecting to Epic's own HTTPS account pages.
+func isEpicContinuationURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "epicgames.com" || strings.HasSuffix(host, ".epicgames.com")
+}
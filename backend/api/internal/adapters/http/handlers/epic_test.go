@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gamedivers.de/api/internal/adapters/stores/epic"
+)
+
+func TestIsEpicContinuationURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://www.epicgames.com/id/login/scope-consent?continuation=abc": true,
+		"https://epicgames.com/id/continue":                                 true,
+		"http://www.epicgames.com/id/login":                                 false,
+		"https://epicgames.com.evil.example/id/login":                       false,
+		"https://evil.example/?next=epicgames.com":                          false,
+		"": false,
+	}
+	for raw, want := range cases {
+		if got := isEpicContinuationURL(raw); got != want {
+			t.Errorf("isEpicContinuationURL(%q) = %t, want %t", raw, got, want)
+		}
+	}
+}
+
+func TestEpicLoginRedirectWithoutCredentials(t *testing.T) {
+	handler := NewEpicHandler("", "", "", "https://gamedivers.de", nil)
+
+	w := httptest.NewRecorder()
+	handler.LoginRedirect(w, httptest.NewRequest(http.MethodGet, "/v1/epic/login", nil))
+
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("expected status 501, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "provider_not_configured") {
+		t.Fatalf("expected provider_not_configured error, got %s", w.Body.String())
+	}
+}
+
+func TestEpicCallbackKeepsStateAcrossScopeConsent(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute)
+	handler := NewEpicHandler("id", "secret", "", "https://gamedivers.de", store)
+
+	var codes []string
+	handler.exchangeCode = func(_ context.Context, code string) (*epic.OAuthTokenResponse, error) {
+		codes = append(codes, code)
+		if code == "first" {
+			return nil, &epic.ScopeConsentError{ContinuationURL: "https://www.epicgames.com/id/login/scope-consent?continuation=abc"}
+		}
+		return nil, errors.New("token endpoint down")
+	}
+
+	state, err := issueOAuthState(httptest.NewRequest(http.MethodGet, "/v1/epic/login", nil), store, "epic")
+	if err != nil {
+		t.Fatalf("issue state: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.Callback(w, httptest.NewRequest(http.MethodGet, "/v1/epic/callback?code=first&state="+state, nil))
+	if w.Code != http.StatusTemporaryRedirect || !strings.HasPrefix(w.Header().Get("Location"), "https://www.epicgames.com/id/login/scope-consent") {
+		t.Fatalf("expected a redirect to the consent page, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// Epic returns to the callback with a new code and the original state.
+	w = httptest.NewRecorder()
+	handler.Callback(w, httptest.NewRequest(http.MethodGet, "/v1/epic/callback?code=second&state="+state, nil))
+	if w.Code == http.StatusBadRequest {
+		t.Fatalf("expected the state to survive the consent round-trip, got %d %s", w.Code, w.Body.String())
+	}
+	if len(codes) != 2 || codes[1] != "second" {
+		t.Fatalf("expected the second code to be exchanged, got %v", codes)
+	}
+
+	// The state is single-use again once the flow has moved past consent.
+	if _, ok, _ := store.Take(context.Background(), state); ok {
+		t.Fatal("expected the state to be consumed by the second callback")
+	}
+}
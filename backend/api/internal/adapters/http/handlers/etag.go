@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// writeJSONWithETag encodes v with a weak ETag and answers 304 Not Modified when the client already has it.
+// The query string is part of the tag so differently filtered views never share one.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		logSafeError(r.Context(), "encode response failed", err)
+		writeInternalError(w)
+		return
+	}
+
+	sum := sha256.New()
+	sum.Write([]byte(r.URL.Query().Encode()))
+	sum.Write([]byte{0})
+	sum.Write(body)
+	etag := `W/"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(body, '\n'))
+}
+
+// etagMatches applies the weak comparison If-None-Match uses.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteJSONWithETagNotModified(t *testing.T) {
+	body := []map[string]int{{"appId": 220}}
+
+	first := httptest.NewRecorder()
+	writeJSONWithETag(first, httptest.NewRequest(http.MethodGet, "/v1/steam/library?steamid=1", nil), body)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", first.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/steam/library?steamid=1", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	second := httptest.NewRecorder()
+	writeJSONWithETag(second, req, body)
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", second.Code)
+	}
+	if second.Body.Len() != 0 {
+		t.Fatalf("expected empty body on 304, got %q", second.Body.String())
+	}
+}
+
+func TestWriteJSONWithETagIncludesQuery(t *testing.T) {
+	body := []map[string]int{}
+
+	all := httptest.NewRecorder()
+	writeJSONWithETag(all, httptest.NewRequest(http.MethodGet, "/v1/steam/library?steamid=1", nil), body)
+	filtered := httptest.NewRecorder()
+	writeJSONWithETag(filtered, httptest.NewRequest(http.MethodGet, "/v1/steam/library?steamid=1&never_played=true", nil), body)
+
+	if all.Header().Get("ETag") == filtered.Header().Get("ETag") {
+		t.Fatal("expected filters to change the ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/steam/library?steamid=1&never_played=true", nil)
+	req.Header.Set("If-None-Match", all.Header().Get("ETag"))
+	w := httptest.NewRecorder()
+	writeJSONWithETag(w, req, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a different filter, got %d", w.Code)
+	}
+}
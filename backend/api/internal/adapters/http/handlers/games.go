@@ -16,11 +16,17 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
 	"gamedivers.de/api/internal/ports/repo"
 )
 
 type GameHandler struct {
 	Repo repo.Repo
+	// GameRoots are the only directories executables may be launched from.
+	// Defaults to the standard GOG Galaxy install locations when empty.
+	GameRoots []string
+	// SteamRoots are Steam client install directories to scan. Defaults per OS when empty.
+	SteamRoots []string
 }
 
 var safeName = regexp.MustCompile("^[\\w .-]{1,120}$")
@@ -130,23 +136,29 @@ func startSteamApp(appID uint64) error {
 	return nil
 }
 
-// GetSteamLibrary retrieves the user's Steam game library
+// GetSteamLibrary lists the Steam games installed in this machine's Steam libraries.
+// The owned library from the Steam Web API is served by SteamHandler at /v1/steam/library.
 // GET /v1/games/steam/library
 func (h *GameHandler) GetSteamLibrary(w http.ResponseWriter, r *http.Request) {
-	// This would need to be implemented with proper Steam API authentication
-	// For now, return a placeholder response
+	games := scanInstalledSteamGames(h.steamRoots())
+	if games == nil {
+		games = []InstalledGame{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]any{
-		"error": "Steam library endpoint not yet implemented. Requires Steam API authentication.",
-	})
+	json.NewEncoder(w).Encode(games)
 }
 
-// GetInstalledGames retrieves games that are synced and installed
+// GetInstalledGames scans the local Steam, Epic and GOG installations for installed games
 // GET /v1/games/installed
 func (h *GameHandler) GetInstalledGames(w http.ResponseWriter, r *http.Request) {
+	games := h.scanInstalledGames()
+	if games == nil {
+		games = []InstalledGame{}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode([]any{})
+	json.NewEncoder(w).Encode(games)
 }
 
 // StartEpicGame starts a synced Epic Games game by its app name
@@ -167,7 +179,7 @@ func (h *GameHandler) StartEpicGame(w http.ResponseWriter, r *http.Request) {
 
 	// Start the Epic game
 	if err := startEpicApp(appName); err != nil {
-		log.Printf("[Epic Games] launch failed")
+		authmw.Logger(r.Context()).Printf("[Epic Games] launch failed")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -336,7 +348,7 @@ func readEpicManifests() ([]EpicManifest, error) {
 func (h *GameHandler) GetEpicLibrary(w http.ResponseWriter, r *http.Request) {
 	manifests, err := readEpicManifests()
 	if err != nil {
-		log.Printf("[Epic Games] library read failed")
+		authmw.Logger(r.Context()).Printf("[Epic Games] library read failed")
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -384,10 +396,14 @@ func (h *GameHandler) StartGOGGame(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Start the GOG game
-	if err := startGOGApp(gameName); err != nil {
-		log.Printf("[GOG Galaxy] launch failed")
+	if err := startGOGApp(gameName, h.gameRoots()); err != nil {
+		authmw.Logger(r.Context()).Printf("[GOG Galaxy] launch failed")
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrUnsafeLaunchPath) {
+			status = http.StatusForbidden
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(map[string]any{
 			"success":   false,
 			"message":   err.Error(),
@@ -405,24 +421,23 @@ func (h *GameHandler) StartGOGGame(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *GameHandler) gameRoots() []string {
+	if len(h.GameRoots) > 0 {
+		return h.GameRoots
+	}
+	return defaultGameRoots()
+}
+
 // startGOGApp launches a GOG Galaxy game
-// Reads the GOG Galaxy configuration files to find the correct game ID and launches the game
-func startGOGApp(gameName string) error {
-	// Try to find the game in GOG Galaxy configuration
-	gameInstallPath, err := findGOGGamePath(gameName)
+// Uses the play task from the game's goggame-*.info file, falling back to directory name matching
+func startGOGApp(gameName string, roots []string) error {
+	target, err := resolveGOGLaunchTarget(gameName, roots)
 	if err != nil {
 		log.Printf("[GOG Galaxy] game path lookup failed")
 		return err
 	}
-
-	if gameInstallPath == "" {
-		return NewStartGameError("GOG Galaxy game not found")
-	}
-
-	// Find the executable in the game directory
-	exePath := findGameExecutable(gameInstallPath)
-	if exePath == "" {
-		return NewStartGameError("game executable not found")
+	if err := validateLaunchPath(target.Exe, roots); err != nil {
+		return err
 	}
 
 	var cmd *exec.Cmd
@@ -430,22 +445,22 @@ func startGOGApp(gameName string) error {
 	switch runtime.GOOS {
 	case "windows":
 		// Execute the game directly
-		cmd = exec.Command(exePath)
+		cmd = exec.Command(target.Exe, target.Args...)
 
 	case "darwin":
 		// On macOS
-		cmd = exec.Command("open", exePath)
+		cmd = exec.Command("open", append([]string{target.Exe, "--args"}, target.Args...)...)
 
 	case "linux":
 		// On Linux
-		cmd = exec.Command(exePath)
+		cmd = exec.Command(target.Exe, target.Args...)
 
 	default:
 		return ErrUnsupportedOS
 	}
 
-	// Set working directory to the game folder for relative path dependencies
-	cmd.Dir = gameInstallPath
+	// Set working directory for relative path dependencies
+	cmd.Dir = target.WorkingDir
 
 	err = cmd.Start()
 	if err != nil {
@@ -461,8 +476,8 @@ func findGameExecutable(gamePath string) string {
 		return ""
 	}
 
-	// Look for common executable names and extensions
-	exePatterns := []string{".exe", ".bat", ".cmd"}
+	// Look for common executable names. Batch files are skipped because they run through cmd.exe.
+	exePatterns := []string{".exe"}
 	priorityNames := []string{"start", "launch", "run", "game"}
 
 	// First pass: look for files with priority names
@@ -506,24 +521,11 @@ type GOGGame struct {
 	ExecutablePath string `json:"executablePath"`
 }
 
-// findGOGGamePath searches for a GOG Galaxy game installation directory
-func findGOGGamePath(gameName string) (string, error) {
+// findGOGGamePath searches the game roots for a GOG Galaxy game installation directory
+func findGOGGamePath(gameName string, roots []string) (string, error) {
 	gameNameLower := strings.ToLower(gameName)
 
-	// GOG Galaxy typically installs games in these locations
-	gogInstallPaths := []string{
-		filepath.Join("C:\\", "Program Files", "GOG Galaxy", "Games"),
-		filepath.Join("C:\\", "Program Files (x86)", "GOG Galaxy", "Games"),
-		filepath.Join("C:\\", "Games"),
-		filepath.Join("C:\\", "GOG Games"),
-	}
-
-	// Also check user's custom installation path from registry or config
-	username := os.Getenv("USERNAME")
-	userPath := filepath.Join("C:\\Users", username, "Games")
-	gogInstallPaths = append(gogInstallPaths, userPath)
-
-	for _, basePath := range gogInstallPaths {
+	for _, basePath := range roots {
 		entries, err := ioutil.ReadDir(basePath)
 		if err != nil {
 			continue
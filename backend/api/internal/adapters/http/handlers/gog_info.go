@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gogInfo is the goggame-<id>.info file GOG Galaxy writes into every game directory
+type gogInfo struct {
+	GameID    string        `json:"gameId"`
+	Name      string        `json:"name"`
+	PlayTasks []gogPlayTask `json:"playTasks"`
+}
+
+// gogPlayTask describes one way to start a game, as declared by GOG
+type gogPlayTask struct {
+	Category   string `json:"category"`
+	IsPrimary  bool   `json:"isPrimary"`
+	Type       string `json:"type"`
+	Path       string `json:"path"`
+	WorkingDir string `json:"workingDir"`
+	Arguments  string `json:"arguments"`
+}
+
+// gogLaunchTarget is a resolved executable with its working directory and arguments
+type gogLaunchTarget struct {
+	Exe        string
+	WorkingDir string
+	Args       []string
+}
+
+// readGOGInfo parses the first goggame-*.info file in gameDir.
+func readGOGInfo(gameDir string) (*gogInfo, bool) {
+	matches, err := filepath.Glob(filepath.Join(gameDir, "goggame-*.info"))
+	if err != nil {
+		return nil, false
+	}
+
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var info gogInfo
+		if err := json.Unmarshal(data, &info); err != nil || info.GameID == "" {
+			continue
+		}
+		return &info, true
+	}
+	return nil, false
+}
+
+// findGOGGameByInfo looks for a game directory whose .info declares the given name or game ID.
+func findGOGGameByInfo(gameNameOrID string, roots []string) (string, *gogInfo) {
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			gameDir := filepath.Join(root, entry.Name())
+			info, ok := readGOGInfo(gameDir)
+			if !ok {
+				continue
+			}
+			if info.GameID == gameNameOrID || strings.EqualFold(info.Name, gameNameOrID) {
+				return gameDir, info
+			}
+		}
+	}
+	return "", nil
+}
+
+// primaryLaunchTarget returns the primary game play task, falling back to the first game FileTask.
+func (info *gogInfo) primaryLaunchTarget(gameDir string) (*gogLaunchTarget, bool) {
+	var chosen *gogPlayTask
+	for i := range info.PlayTasks {
+		task := &info.PlayTasks[i]
+		if task.Type != "FileTask" || task.Path == "" {
+			continue
+		}
+		if task.IsPrimary {
+			chosen = task
+			break
+		}
+		if chosen == nil && (task.Category == "" || task.Category == "game") {
+			chosen = task
+		}
+	}
+	if chosen == nil {
+		return nil, false
+	}
+
+	exe := filepath.Join(gameDir, gogRelativePath(chosen.Path))
+	if !isWithinDir(exe, gameDir) {
+		return nil, false
+	}
+
+	workingDir := filepath.Dir(exe)
+	if chosen.WorkingDir != "" {
+		workingDir = filepath.Join(gameDir, gogRelativePath(chosen.WorkingDir))
+		if workingDir != filepath.Clean(gameDir) && !isWithinDir(workingDir, gameDir) {
+			return nil, false
+		}
+	}
+
+	return &gogLaunchTarget{
+		Exe:        exe,
+		WorkingDir: workingDir,
+		Args:       splitGOGArguments(chosen.Arguments),
+	}, true
+}
+
+// gogRelativePath converts the Windows-style relative paths used in .info files.
+func gogRelativePath(path string) string {
+	return filepath.FromSlash(strings.ReplaceAll(path, `\`, "/"))
+}
+
+// splitGOGArguments splits a play task argument string, honouring double quotes.
+func splitGOGArguments(raw string) []string {
+	var args []string
+	var current strings.Builder
+	inQuotes := false
+	hasArg := false
+
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// resolveGOGLaunchTarget prefers the play task declared in the game's .info file and only
+// falls back to matching directory names and guessing the executable when there is none.
+func resolveGOGLaunchTarget(gameNameOrID string, roots []string) (*gogLaunchTarget, error) {
+	if gameDir, info := findGOGGameByInfo(gameNameOrID, roots); info != nil {
+		if target, ok := info.primaryLaunchTarget(gameDir); ok {
+			return target, nil
+		}
+	}
+
+	gameInstallPath, err := findGOGGamePath(gameNameOrID, roots)
+	if err != nil {
+		return nil, err
+	}
+	if gameInstallPath == "" {
+		return nil, NewStartGameError("GOG Galaxy game not found")
+	}
+
+	if info, ok := readGOGInfo(gameInstallPath); ok {
+		if target, ok := info.primaryLaunchTarget(gameInstallPath); ok {
+			return target, nil
+		}
+	}
+
+	exePath := findGameExecutable(gameInstallPath)
+	if exePath == "" {
+		return nil, NewStartGameError("game executable not found")
+	}
+	return &gogLaunchTarget{Exe: exePath, WorkingDir: gameInstallPath}, nil
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeGOGInfo(t *testing.T, gameDir, gameID, body string) {
+	t.Helper()
+	if err := os.MkdirAll(gameDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(gameDir, "goggame-"+gameID+".info"), []byte(body), 0o644); err != nil {
+		t.Fatalf("write info: %v", err)
+	}
+}
+
+func TestResolveGOGLaunchTargetUsesPrimaryPlayTask(t *testing.T) {
+	root := t.TempDir()
+
+	// Both directory names contain "Witcher"; the .info name must decide.
+	demoDir := filepath.Join(root, "The Witcher Demo")
+	writeTestExecutable(t, filepath.Join(demoDir, "demo.exe"))
+
+	gameDir := filepath.Join(root, "The Witcher 3 Wild Hunt GOTY")
+	writeTestExecutable(t, filepath.Join(gameDir, "bin", "x64", "witcher3.exe"))
+	writeTestExecutable(t, filepath.Join(gameDir, "REDprelauncher.exe"))
+	writeGOGInfo(t, gameDir, "1207664663", `{
+		"gameId": "1207664663",
+		"name": "The Witcher 3: Wild Hunt - Game of the Year Edition",
+		"playTasks": [
+			{"category": "launcher", "type": "FileTask", "path": "REDprelauncher.exe"},
+			{"category": "game", "isPrimary": true, "type": "FileTask", "path": "bin\\x64\\witcher3.exe", "workingDir": "bin\\x64", "arguments": "-debugscripts \"-net port\""},
+			{"category": "document", "type": "URLTask", "link": "https://www.gog.com/support"}
+		]
+	}`)
+
+	for _, lookup := range []string{"the witcher 3: wild hunt - game of the year edition", "1207664663"} {
+		target, err := resolveGOGLaunchTarget(lookup, []string{root})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", lookup, err)
+		}
+		if want := filepath.Join(gameDir, "bin", "x64", "witcher3.exe"); target.Exe != want {
+			t.Fatalf("%s: expected exe %q, got %q", lookup, want, target.Exe)
+		}
+		if want := filepath.Join(gameDir, "bin", "x64"); target.WorkingDir != want {
+			t.Fatalf("%s: expected working dir %q, got %q", lookup, want, target.WorkingDir)
+		}
+		if want := []string{"-debugscripts", "-net port"}; !reflect.DeepEqual(target.Args, want) {
+			t.Fatalf("%s: expected args %q, got %q", lookup, want, target.Args)
+		}
+		if err := validateLaunchPath(target.Exe, []string{root}); err != nil {
+			t.Fatalf("%s: resolved target failed validation: %v", lookup, err)
+		}
+	}
+}
+
+func TestResolveGOGLaunchTargetFallsBackWithoutInfo(t *testing.T) {
+	root := t.TempDir()
+	gameDir := filepath.Join(root, "Captain Blood Demo")
+	writeTestExecutable(t, filepath.Join(gameDir, "game.exe"))
+
+	target, err := resolveGOGLaunchTarget("Captain Blood Demo", []string{root})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Exe != filepath.Join(gameDir, "game.exe") || target.WorkingDir != gameDir {
+		t.Fatalf("unexpected fallback target %+v", target)
+	}
+}
+
+func TestGOGPlayTaskCannotEscapeGameDir(t *testing.T) {
+	gameDir := filepath.Join(t.TempDir(), "Game")
+	info := &gogInfo{GameID: "1", PlayTasks: []gogPlayTask{
+		{IsPrimary: true, Type: "FileTask", Path: `..\..\windows\system32\cmd.exe`},
+	}}
+
+	if _, ok := info.primaryLaunchTarget(gameDir); ok {
+		t.Fatal("expected play task outside the game directory to be rejected")
+	}
+}
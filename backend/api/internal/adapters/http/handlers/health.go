@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheck probes a single dependency and returns an error when it is unhealthy.
+type HealthCheck func(ctx context.Context) error
+
+// HealthHandler reports dependency health for readiness probes
+type HealthHandler struct {
+	Checks  map[string]HealthCheck
+	Timeout time.Duration
+}
+
+// HealthResponse is the readiness response with a status per dependency
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Live only confirms that the process is up and serving requests
+// GET /health
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// Ready runs all dependency checks concurrently and returns 503 when any of them fails
+// GET /healthz, GET /readyz
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	resp := HealthResponse{Status: "ok", Checks: make(map[string]string, len(h.Checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range h.Checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+			status := "ok"
+			if err := check(ctx); err != nil {
+				logSafeError(r.Context(), "health check "+name+" failed", err)
+				status = "unavailable"
+			}
+			mu.Lock()
+			resp.Checks[name] = status
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	for _, status := range resp.Checks {
+		if status != "ok" {
+			resp.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
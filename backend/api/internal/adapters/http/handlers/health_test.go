@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthReadyReportsFailingDependency(t *testing.T) {
+	handler := &HealthHandler{Checks: map[string]HealthCheck{
+		"database": func(ctx context.Context) error { return errors.New("connection refused") },
+		"keycloak": func(ctx context.Context) error { return nil },
+	}}
+
+	w := httptest.NewRecorder()
+	handler.Ready(w, httptest.NewRequest("GET", "/readyz", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+
+	var resp HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Checks["database"] != "unavailable" || resp.Checks["keycloak"] != "ok" {
+		t.Fatalf("unexpected checks %v", resp.Checks)
+	}
+}
+
+func TestHealthReadyAllHealthy(t *testing.T) {
+	handler := &HealthHandler{Checks: map[string]HealthCheck{
+		"keycloak": func(ctx context.Context) error { return nil },
+	}}
+
+	w := httptest.NewRecorder()
+	handler.Ready(w, httptest.NewRequest("GET", "/healthz", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
-	"log"
+	"context"
 	"net/http"
 	"regexp"
+
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
 )
 
 var sensitiveQueryPattern = regexp.MustCompile(`(?i)(key|access_token|refresh_token|client_secret)=([^&\s]+)`)
@@ -12,11 +14,12 @@ func sanitizeErrorMessage(msg string) string {
 	return sensitiveQueryPattern.ReplaceAllString(msg, `$1=[REDACTED]`)
 }
 
-func logSafeError(prefix string, err error) {
+// logSafeError logs err with secrets redacted, tagged with the request's correlation ID.
+func logSafeError(ctx context.Context, prefix string, err error) {
 	if err == nil {
 		return
 	}
-	log.Printf("%s: %s", prefix, sanitizeErrorMessage(err.Error()))
+	authmw.Logger(ctx).Printf("%s: %s", prefix, sanitizeErrorMessage(err.Error()))
 }
 
 func writeBadGateway(w http.ResponseWriter) {
@@ -27,3 +30,7 @@ func writeInternalError(w http.ResponseWriter) {
 	http.Error(w, "internal server error", http.StatusInternalServerError)
 }
 
+// writeProviderNotConfigured tells self-hosters which store integration is missing its credentials.
+func writeProviderNotConfigured(w http.ResponseWriter, provider string) {
+	writeError(w, http.StatusNotImplemented, "provider_not_configured", provider+" integration is not configured on this server")
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InstalledGame is a game found installed on this machine by one of the launcher scanners
+type InstalledGame struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Platform    string `json:"platform"`
+	InstallPath string `json:"installPath"`
+}
+
+// scanInstalledGames collects installed games from Steam, Epic and GOG.
+// Launchers that are not installed simply contribute nothing.
+func (h *GameHandler) scanInstalledGames() []InstalledGame {
+	games := scanInstalledSteamGames(h.steamRoots())
+	games = append(games, scanInstalledEpicGames()...)
+	games = append(games, scanInstalledGOGGames(h.gameRoots())...)
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return strings.ToLower(games[i].Name) < strings.ToLower(games[j].Name)
+	})
+	return games
+}
+
+func (h *GameHandler) steamRoots() []string {
+	if len(h.SteamRoots) > 0 {
+		return h.SteamRoots
+	}
+	return defaultSteamRoots()
+}
+
+// scanInstalledEpicGames lists Epic manifests whose install location still exists.
+func scanInstalledEpicGames() []InstalledGame {
+	manifests, err := readEpicManifests()
+	if err != nil {
+		return nil
+	}
+
+	games := make([]InstalledGame, 0, len(manifests))
+	for _, manifest := range manifests {
+		if manifest.InstallPath == "" {
+			continue
+		}
+		if info, err := os.Stat(manifest.InstallPath); err != nil || !info.IsDir() {
+			continue
+		}
+
+		name := manifest.DisplayName
+		if name == "" {
+			name = manifest.AppName
+		}
+		games = append(games, InstalledGame{
+			ID:          manifest.AppName,
+			Name:        name,
+			Platform:    "epic",
+			InstallPath: manifest.InstallPath,
+		})
+	}
+	return games
+}
+
+// scanInstalledGOGGames lists game directories under the game roots that carry a GOG .info file.
+func scanInstalledGOGGames(roots []string) []InstalledGame {
+	var games []InstalledGame
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			gameDir := filepath.Join(root, entry.Name())
+			info, ok := readGOGInfo(gameDir)
+			if !ok {
+				continue
+			}
+			name := info.Name
+			if name == "" {
+				name = entry.Name()
+			}
+			games = append(games, InstalledGame{
+				ID:          info.GameID,
+				Name:        name,
+				Platform:    "gog",
+				InstallPath: gameDir,
+			})
+		}
+	}
+	return games
+}
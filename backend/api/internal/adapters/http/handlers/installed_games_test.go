@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestParseVDF(t *testing.T) {
+	parsed, err := parseVDF(`// comment
+"libraryfolders"
+{
+	"0"
+	{
+		"path"		"C:\\Program Files (x86)\\Steam"
+		"apps" { "228980" "123" }
+	}
+	"1"		"D:\\SteamLibrary"
+}`)
+	if err != nil {
+		t.Fatalf("parseVDF: %v", err)
+	}
+
+	libraries := parsed.child("LibraryFolders")
+	if got := libraries.child("0").str("path"); got != `C:\Program Files (x86)\Steam` {
+		t.Fatalf("path = %q", got)
+	}
+	if got := libraries.str("1"); got != `D:\SteamLibrary` {
+		t.Fatalf("legacy path = %q", got)
+	}
+
+	if _, err := parseVDF(`"AppState" { "appid" "1"`); err == nil {
+		t.Fatal("expected error for unterminated object")
+	}
+}
+
+func TestScanInstalledSteamGamesReadsAllLibraries(t *testing.T) {
+	steamRoot := t.TempDir()
+	extraLibrary := t.TempDir()
+
+	writeTestFile(t, filepath.Join(steamRoot, "steamapps", "libraryfolders.vdf"), `"libraryfolders"
+{
+	"0" { "path" "`+strings.ReplaceAll(steamRoot, `\`, `\\`)+`" }
+	"1" { "path" "`+strings.ReplaceAll(extraLibrary, `\`, `\\`)+`" }
+}`)
+	writeTestFile(t, filepath.Join(steamRoot, "steamapps", "appmanifest_620.acf"), `"AppState"
+{
+	"appid"		"620"
+	"name"		"Portal 2"
+	"StateFlags"		"4"
+	"installdir"		"Portal 2"
+}`)
+	// Still downloading: must not be reported as installed.
+	writeTestFile(t, filepath.Join(steamRoot, "steamapps", "appmanifest_570.acf"), `"AppState"
+{
+	"appid"		"570"
+	"name"		"Dota 2"
+	"StateFlags"		"1026"
+	"installdir"		"dota 2 beta"
+}`)
+	writeTestFile(t, filepath.Join(extraLibrary, "steamapps", "appmanifest_400.acf"), `"AppState"
+{
+	"appid"		"400"
+	"name"		"Portal"
+	"StateFlags"		"4"
+	"installdir"		"Portal"
+}`)
+
+	games := scanInstalledSteamGames([]string{steamRoot, filepath.Join(t.TempDir(), "missing")})
+	if len(games) != 2 {
+		t.Fatalf("expected 2 installed games, got %+v", games)
+	}
+
+	byID := map[string]InstalledGame{}
+	for _, game := range games {
+		byID[game.ID] = game
+	}
+	if byID["620"].Name != "Portal 2" || byID["620"].InstallPath != filepath.Join(steamRoot, "steamapps", "common", "Portal 2") {
+		t.Fatalf("unexpected Portal 2 entry: %+v", byID["620"])
+	}
+	if byID["400"].InstallPath != filepath.Join(extraLibrary, "steamapps", "common", "Portal") {
+		t.Fatalf("unexpected Portal entry: %+v", byID["400"])
+	}
+}
+
+func TestScanInstalledEpicGamesSkipsRemovedInstalls(t *testing.T) {
+	programData := t.TempDir()
+	t.Setenv("PROGRAMDATA", programData)
+
+	installDir := t.TempDir()
+	manifestDir := filepath.Join(programData, "Epic", "EpicGamesLauncher", "Data", "Manifests")
+	writeTestFile(t, filepath.Join(manifestDir, "a.item"), `{"AppName":"Fortnite","DisplayName":"Fortnite","InstallLocation":`+jsonString(t, installDir)+`}`)
+	writeTestFile(t, filepath.Join(manifestDir, "b.item"), `{"AppName":"Gone","DisplayName":"Gone","InstallLocation":`+jsonString(t, filepath.Join(installDir, "removed"))+`}`)
+
+	games := scanInstalledEpicGames()
+	if len(games) != 1 || games[0].ID != "Fortnite" || games[0].Platform != "epic" {
+		t.Fatalf("unexpected epic games: %+v", games)
+	}
+}
+
+func TestGetInstalledGamesWithoutLaunchers(t *testing.T) {
+	t.Setenv("PROGRAMDATA", "")
+	h := &GameHandler{
+		SteamRoots: []string{filepath.Join(t.TempDir(), "Steam")},
+		GameRoots:  []string{filepath.Join(t.TempDir(), "GOG Games")},
+	}
+
+	rec := httptest.NewRecorder()
+	h.GetInstalledGames(rec, httptest.NewRequest(http.MethodGet, "/v1/games/installed", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != "[]" {
+		t.Fatalf("body = %s", body)
+	}
+}
+
+func TestGetInstalledGamesIncludesGOG(t *testing.T) {
+	t.Setenv("PROGRAMDATA", "")
+	root := t.TempDir()
+	writeGOGInfo(t, filepath.Join(root, "Witcher 3"), "1207664663", `{"gameId":"1207664663","name":"The Witcher 3"}`)
+
+	h := &GameHandler{
+		SteamRoots: []string{filepath.Join(t.TempDir(), "Steam")},
+		GameRoots:  []string{root},
+	}
+
+	rec := httptest.NewRecorder()
+	h.GetInstalledGames(rec, httptest.NewRequest(http.MethodGet, "/v1/games/installed", nil))
+
+	var games []InstalledGame
+	if err := json.NewDecoder(rec.Body).Decode(&games); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(games) != 1 || games[0].ID != "1207664663" || games[0].Platform != "gog" {
+		t.Fatalf("unexpected games: %+v", games)
+	}
+}
+
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b)
+}
+
+func TestGetSteamLibraryListsOnlyInstalledGames(t *testing.T) {
+	steamRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(steamRoot, "steamapps", "appmanifest_620.acf"), `"AppState" { "appid" "620" "name" "Portal 2" "StateFlags" "4" "installdir" "Portal 2" }`)
+	writeTestFile(t, filepath.Join(steamRoot, "steamapps", "appmanifest_570.acf"), `"AppState" { "appid" "570" "name" "Dota 2" "StateFlags" "2" "installdir" "dota 2 beta" }`)
+
+	h := &GameHandler{SteamRoots: []string{steamRoot}}
+	rec := httptest.NewRecorder()
+	h.GetSteamLibrary(rec, httptest.NewRequest(http.MethodGet, "/v1/games/steam/library", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var games []InstalledGame
+	if err := json.NewDecoder(rec.Body).Decode(&games); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(games) != 1 || games[0].Name != "Portal 2" || games[0].Platform != "steam" {
+		t.Fatalf("unexpected games: %+v", games)
+	}
+}
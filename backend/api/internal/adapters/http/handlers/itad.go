@@ -1,20 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"gamedivers.de/api/internal/adapters/stores/hltb"
 	"gamedivers.de/api/internal/adapters/stores/itad"
+	"gamedivers.de/api/internal/ports/repo"
 )
 
 // ITADHandler handles IsThereAnyDeal API requests
 type ITADHandler struct {
 	Client *itad.Client
+	// Users resolves a caller's stored preferred region (optional)
+	Users repo.UserRepo
+	// DefaultCountry is used when neither the request nor the user specify one
+	DefaultCountry string
+	// Completion adds HowLongToBeat estimates to game details (optional)
+	Completion CompletionEstimator
 }
 
+// CompletionEstimator looks up how long a game takes to beat
+type CompletionEstimator interface {
+	Estimate(ctx context.Context, title string) (*hltb.Estimate, error)
+}
+
+// completionLookupTimeout keeps a slow HLTB search from holding up game details.
+const completionLookupTimeout = 5 * time.Second
+
 // Search handles game search requests
 // GET /v1/itad/search?q=<query>&limit=<limit>
 func (h *ITADHandler) Search(w http.ResponseWriter, r *http.Request) {
@@ -33,7 +51,7 @@ func (h *ITADHandler) Search(w http.ResponseWriter, r *http.Request) {
 
 	data, err := h.Client.Search(r.Context(), query, limit)
 	if err != nil {
-		logSafeError("itad search failed", err)
+		logSafeError(r.Context(), "itad search failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -53,7 +71,7 @@ func (h *ITADHandler) GetGameInfo(w http.ResponseWriter, r *http.Request) {
 
 	data, err := h.Client.GetGameInfo(r.Context(), gameID)
 	if err != nil {
-		logSafeError("itad game info failed", err)
+		logSafeError(r.Context(), "itad game info failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -71,14 +89,15 @@ func (h *ITADHandler) GetGamePrices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	country := r.URL.Query().Get("country")
-	if country == "" {
-		country = "DE" // default to Germany
+	country, ok := h.resolveCountry(r)
+	if !ok {
+		http.Error(w, "invalid country code", http.StatusBadRequest)
+		return
 	}
 
 	data, err := h.Client.GetGamePrices(r.Context(), gameID, country)
 	if err != nil {
-		logSafeError("itad game prices failed", err)
+		logSafeError(r.Context(), "itad game prices failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -96,14 +115,15 @@ func (h *ITADHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	country := r.URL.Query().Get("country")
-	if country == "" {
-		country = "DE"
+	country, ok := h.resolveCountry(r)
+	if !ok {
+		http.Error(w, "invalid country code", http.StatusBadRequest)
+		return
 	}
 
 	data, err := h.Client.GetOverview(r.Context(), ids, country)
 	if err != nil {
-		logSafeError("itad overview failed", err)
+		logSafeError(r.Context(), "itad overview failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -112,6 +132,48 @@ func (h *ITADHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
+// maxBulkPriceIDs bounds how many games one bulk price request may ask for.
+const maxBulkPriceIDs = 500
+
+// GetPrices returns current deals for many games at once, keyed by game ID
+// GET /v1/itad/prices?id=<id>&id=<id>&country=<cc>
+func (h *ITADHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	seen := map[string]struct{}{}
+	var ids []string
+	for _, id := range r.URL.Query()["id"] {
+		if _, dup := seen[id]; id == "" || dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		http.Error(w, "missing id parameter(s)", http.StatusBadRequest)
+		return
+	}
+	if len(ids) > maxBulkPriceIDs {
+		writeError(w, http.StatusBadRequest, "too_many_ids", "At most "+strconv.Itoa(maxBulkPriceIDs)+" ids per request")
+		return
+	}
+
+	country, ok := h.resolveCountry(r)
+	if !ok {
+		http.Error(w, "invalid country code", http.StatusBadRequest)
+		return
+	}
+
+	prices, err := h.Client.Prices(r.Context(), ids, country)
+	if err != nil {
+		logSafeError(r.Context(), "itad bulk prices failed", err)
+		writeBadGateway(w)
+		return
+	}
+	linkDealStores(prices)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prices)
+}
+
 // GetHistoricalLow handles historical low price requests
 // GET /v1/itad/games/{gameId}/historylow?country=<cc>
 func (h *ITADHandler) GetHistoricalLow(w http.ResponseWriter, r *http.Request) {
@@ -121,14 +183,15 @@ func (h *ITADHandler) GetHistoricalLow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	country := r.URL.Query().Get("country")
-	if country == "" {
-		country = "DE"
+	country, ok := h.resolveCountry(r)
+	if !ok {
+		http.Error(w, "invalid country code", http.StatusBadRequest)
+		return
 	}
 
 	data, err := h.Client.GetHistoricalLow(r.Context(), gameID, country)
 	if err != nil {
-		logSafeError("itad historylow failed", err)
+		logSafeError(r.Context(), "itad historylow failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -140,14 +203,15 @@ func (h *ITADHandler) GetHistoricalLow(w http.ResponseWriter, r *http.Request) {
 // GetStores handles available stores request
 // GET /v1/itad/stores?country=<cc>
 func (h *ITADHandler) GetStores(w http.ResponseWriter, r *http.Request) {
-	country := r.URL.Query().Get("country")
-	if country == "" {
-		country = "DE"
+	country, ok := h.resolveCountry(r)
+	if !ok {
+		http.Error(w, "invalid country code", http.StatusBadRequest)
+		return
 	}
 
 	data, err := h.Client.GetStores(r.Context(), country)
 	if err != nil {
-		logSafeError("itad stores failed", err)
+		logSafeError(r.Context(), "itad stores failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -165,22 +229,23 @@ func (h *ITADHandler) GetGameDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	country := r.URL.Query().Get("country")
-	if country == "" {
-		country = "DE"
+	country, ok := h.resolveCountry(r)
+	if !ok {
+		http.Error(w, "invalid country code", http.StatusBadRequest)
+		return
 	}
 
 	// Fetch both info and prices
 	infoData, err := h.Client.GetGameInfo(r.Context(), gameID)
 	if err != nil {
-		logSafeError("itad game details info failed", err)
+		logSafeError(r.Context(), "itad game details info failed", err)
 		writeBadGateway(w)
 		return
 	}
 
 	pricesData, err := h.Client.GetGamePrices(r.Context(), gameID, country)
 	if err != nil {
-		logSafeError("itad game details prices failed", err)
+		logSafeError(r.Context(), "itad game details prices failed", err)
 		writeBadGateway(w)
 		return
 	}
@@ -196,8 +261,49 @@ func (h *ITADHandler) GetGameDetails(w http.ResponseWriter, r *http.Request) {
 		"info":       infoData,
 		"prices":     pricesData,
 		"historyLow": historyData,
+		"completion": h.completionEstimate(r, infoData),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// completionEstimate returns the HLTB estimate for the game described by infoData, or null
+// when estimates are disabled, the title is unknown, or the lookup fails.
+func (h *ITADHandler) completionEstimate(r *http.Request, infoData json.RawMessage) json.RawMessage {
+	null := json.RawMessage(`null`)
+	if h.Completion == nil {
+		return null
+	}
+
+	var info struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(infoData, &info); err != nil || info.Title == "" {
+		return null
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), completionLookupTimeout)
+	defer cancel()
+
+	estimate, err := h.Completion.Estimate(ctx, info.Title)
+	if err != nil {
+		logSafeError(r.Context(), "hltb estimate failed", err)
+		return null
+	}
+	if estimate == nil {
+		return null
+	}
+
+	data, err := json.Marshal(estimate)
+	if err != nil {
+		return null
+	}
+	return data
+}
+
+// resolveCountry picks the price region from ?country=, then the user's stored
+// preference, then the configured default. It reports false for invalid codes.
+func (h *ITADHandler) resolveCountry(r *http.Request) (string, bool) {
+	return requestCountry(r, r.URL.Query().Get("country"), h.Users, h.DefaultCountry)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"strings"
+
+	"gamedivers.de/api/internal/adapters/stores/itad"
+)
+
+// itadShopStores maps lower-cased ITAD shop names to the store ids used across the API.
+var itadShopStores = map[string]string{
+	"steam":            "steam",
+	"epic game store":  "epic",
+	"epic games store": "epic",
+	"gog":              "gog",
+	"gog.com":          "gog",
+}
+
+// mapITADShopToStore returns our store id for an ITAD shop name, or "" if we don't track that shop.
+func mapITADShopToStore(shopName string) string {
+	return itadShopStores[strings.ToLower(strings.TrimSpace(shopName))]
+}
+
+// linkDealStores tags each deal with our store id. Deal URLs are left as ITAD's
+// redirect links, which land on the shop's page for tracked and untracked shops alike.
+func linkDealStores(prices map[string]itad.GamePrices) {
+	for id, game := range prices {
+		for i := range game.Deals {
+			game.Deals[i].StoreID = mapITADShopToStore(game.Deals[i].Shop.Name)
+		}
+		prices[id] = game
+	}
+}
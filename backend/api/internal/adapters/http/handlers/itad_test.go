@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gamedivers.de/api/internal/adapters/stores/hltb"
+	"gamedivers.de/api/internal/adapters/stores/itad"
+)
+
+type fakeCompletion struct {
+	estimate *hltb.Estimate
+	err      error
+	title    string
+}
+
+func (f *fakeCompletion) Estimate(_ context.Context, title string) (*hltb.Estimate, error) {
+	f.title = title
+	return f.estimate, f.err
+}
+
+func TestITADRejectsInvalidCountry(t *testing.T) {
+	handler := &ITADHandler{DefaultCountry: "DE"}
+
+	for _, country := range []string{"XX", "deu", "1", "de%3Bdrop"} {
+		req := httptest.NewRequest("GET", "/itad/stores?country="+country, nil)
+		w := httptest.NewRecorder()
+
+		handler.GetStores(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("country %q: expected status 400, got %d", country, w.Code)
+		}
+	}
+}
+
+func TestITADResolveCountry(t *testing.T) {
+	handler := &ITADHandler{DefaultCountry: "us"}
+
+	req := httptest.NewRequest("GET", "/itad/stores?country=gb", nil)
+	if got, ok := handler.resolveCountry(req); !ok || got != "GB" {
+		t.Fatalf("expected query country GB, got %q (ok=%t)", got, ok)
+	}
+
+	req = httptest.NewRequest("GET", "/itad/stores", nil)
+	if got, ok := handler.resolveCountry(req); !ok || got != "US" {
+		t.Fatalf("expected default country US, got %q (ok=%t)", got, ok)
+	}
+
+	handler.DefaultCountry = "nowhere"
+	if got, ok := handler.resolveCountry(req); !ok || got != fallbackCountry {
+		t.Fatalf("expected fallback country %s, got %q (ok=%t)", fallbackCountry, got, ok)
+	}
+}
+
+func TestITADCompletionEstimate(t *testing.T) {
+	hours := 13.0
+	fake := &fakeCompletion{estimate: &hltb.Estimate{GameID: 4063, Name: "Half-Life 2", MainStoryHours: &hours}}
+	handler := &ITADHandler{Completion: fake}
+	req := httptest.NewRequest("GET", "/itad/games/abc", nil)
+
+	got := handler.completionEstimate(req, json.RawMessage(`{"title":"Half-Life 2","type":"game"}`))
+	if fake.title != "Half-Life 2" {
+		t.Fatalf("expected lookup by title, got %q", fake.title)
+	}
+	var estimate hltb.Estimate
+	if err := json.Unmarshal(got, &estimate); err != nil || estimate.MainStoryHours == nil || *estimate.MainStoryHours != 13 {
+		t.Fatalf("unexpected estimate %s (%v)", got, err)
+	}
+
+	fake.err = errors.New("hltb down")
+	if got := handler.completionEstimate(req, json.RawMessage(`{"title":"Half-Life 2"}`)); string(got) != "null" {
+		t.Fatalf("expected null on lookup failure, got %s", got)
+	}
+
+	handler.Completion = nil
+	if got := handler.completionEstimate(req, json.RawMessage(`{"title":"Half-Life 2"}`)); string(got) != "null" {
+		t.Fatalf("expected null when disabled, got %s", got)
+	}
+}
+
+func TestMapITADShopToStore(t *testing.T) {
+	cases := map[string]string{
+		"Steam":           "steam",
+		"Epic Game Store": "epic",
+		"GOG":             "gog",
+		" gog.com ":       "gog",
+		"Humble Store":    "",
+		"":                "",
+	}
+	for shop, want := range cases {
+		if got := mapITADShopToStore(shop); got != want {
+			t.Errorf("mapITADShopToStore(%q) = %q, want %q", shop, got, want)
+		}
+	}
+}
+
+func TestLinkDealStores(t *testing.T) {
+	prices := map[string]itad.GamePrices{
+		"g1": {ID: "g1", Deals: []itad.Deal{
+			{Shop: itad.Shop{ID: 61, Name: "Steam"}, URL: "https://itad.link/steam"},
+			{Shop: itad.Shop{ID: 37, Name: "Humble Store"}, URL: "https://itad.link/humble"},
+		}},
+	}
+
+	linkDealStores(prices)
+
+	deals := prices["g1"].Deals
+	if deals[0].StoreID != "steam" || deals[0].URL != "https://itad.link/steam" {
+		t.Fatalf("unexpected steam deal %+v", deals[0])
+	}
+	if deals[1].StoreID != "" || deals[1].URL != "https://itad.link/humble" {
+		t.Fatalf("untracked shop should keep only the ITAD URL, got %+v", deals[1])
+	}
+}
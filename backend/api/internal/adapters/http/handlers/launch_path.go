@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ErrUnsafeLaunchPath is returned when an executable path fails validation and is not launched.
+var ErrUnsafeLaunchPath = errors.New("unsafe launch path")
+
+// forbiddenPathChars are rejected outright. Games are started with exec.Command and no shell,
+// so quotes, & or % are ordinary file name characters (e.g. "Baldur's Gate 3"); NUL truncates
+// paths in the OS APIs and line breaks have no place in a file name.
+const forbiddenPathChars = "\r\n\x00"
+
+// defaultGameRoots are the directories GOG Galaxy installs games into by default.
+func defaultGameRoots() []string {
+	roots := []string{
+		filepath.Join("C:\\", "Program Files", "GOG Galaxy", "Games"),
+		filepath.Join("C:\\", "Program Files (x86)", "GOG Galaxy", "Games"),
+		filepath.Join("C:\\", "Games"),
+		filepath.Join("C:\\", "GOG Games"),
+	}
+
+	// Also check user's custom installation path
+	if username := os.Getenv("USERNAME"); username != "" {
+		roots = append(roots, filepath.Join("C:\\Users", username, "Games"))
+	}
+	return roots
+}
+
+// validateLaunchPath makes sure exePath is an absolute path to an existing executable file
+// inside one of the allowed game roots before it is passed to exec.Command.
+func validateLaunchPath(exePath string, roots []string) error {
+	if exePath == "" {
+		return fmt.Errorf("%w: empty path", ErrUnsafeLaunchPath)
+	}
+	if strings.HasPrefix(exePath, `\\`) || strings.HasPrefix(exePath, "//") {
+		return fmt.Errorf("%w: UNC paths are not allowed", ErrUnsafeLaunchPath)
+	}
+	if strings.ContainsAny(exePath, forbiddenPathChars) {
+		return fmt.Errorf("%w: path contains control characters", ErrUnsafeLaunchPath)
+	}
+	if !filepath.IsAbs(exePath) {
+		return fmt.Errorf("%w: path is not absolute", ErrUnsafeLaunchPath)
+	}
+
+	// Resolve symlinks so a link inside a game root can't point outside of it.
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(exePath))
+	if err != nil {
+		return fmt.Errorf("%w: path does not exist", ErrUnsafeLaunchPath)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: not a regular file", ErrUnsafeLaunchPath)
+	}
+	if !isExecutableFile(resolved, info) {
+		return fmt.Errorf("%w: not an executable", ErrUnsafeLaunchPath)
+	}
+
+	for _, root := range roots {
+		if root == "" || !filepath.IsAbs(root) {
+			continue
+		}
+		resolvedRoot, err := filepath.EvalSymlinks(filepath.Clean(root))
+		if err != nil {
+			continue
+		}
+		if isWithinDir(resolved, resolvedRoot) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: path is outside the allowed game directories", ErrUnsafeLaunchPath)
+}
+
+func isExecutableFile(path string, info os.FileInfo) bool {
+	if strings.EqualFold(filepath.Ext(path), ".exe") {
+		return true
+	}
+	return runtime.GOOS != "windows" && info.Mode().Perm()&0o111 != 0
+}
+
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestExecutable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("MZ"), 0o755); err != nil {
+		t.Fatalf("write executable: %v", err)
+	}
+}
+
+func TestValidateLaunchPathAcceptsGameUnderRoot(t *testing.T) {
+	root := t.TempDir()
+	exe := filepath.Join(root, "Captain Blood", "game.exe")
+	writeTestExecutable(t, exe)
+
+	if err := validateLaunchPath(exe, []string{root}); err != nil {
+		t.Fatalf("expected valid launch path, got %v", err)
+	}
+}
+
+func TestValidateLaunchPathAllowsPunctuationInNames(t *testing.T) {
+	root := t.TempDir()
+	// No shell is involved, so quotes and similar characters are ordinary name characters.
+	for _, dir := range []string{"Baldur's Gate 3", "Tom Clancy's Rainbow Six® Siege", "Half-Life 2 (100%)", "Rock & Roll Racing!"} {
+		exe := filepath.Join(root, dir, "game.exe")
+		writeTestExecutable(t, exe)
+
+		if err := validateLaunchPath(exe, []string{root}); err != nil {
+			t.Errorf("%s: expected valid launch path, got %v", dir, err)
+		}
+	}
+}
+
+func TestValidateLaunchPathRejectsUnsafePaths(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "games")
+	writeTestExecutable(t, filepath.Join(root, "Game", "game.exe"))
+	outside := filepath.Join(base, "outside", "evil.exe")
+	writeTestExecutable(t, outside)
+
+	link := filepath.Join(root, "Game", "linked.exe")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	cases := map[string]string{
+		"relative traversal": `..\..\windows\system32\cmd.exe`,
+		"absolute traversal": filepath.Join(root, "Game", "..", "..", "outside", "evil.exe"),
+		"outside root":       outside,
+		"symlink escape":     link,
+		"unc path":           `\\attacker\share\game.exe`,
+		"appended command":   filepath.Join(root, "Game", "game.exe") + " & calc.exe",
+		"line break":         filepath.Join(root, "Game", "game.exe") + "\ncalc.exe",
+		"nul byte":           filepath.Join(root, "Game", "game.exe") + "\x00.txt",
+		"missing file":       filepath.Join(root, "Game", "missing.exe"),
+		"directory":          filepath.Join(root, "Game"),
+	}
+
+	for name, path := range cases {
+		if err := validateLaunchPath(path, []string{root}); !errors.Is(err, ErrUnsafeLaunchPath) {
+			t.Errorf("%s: expected ErrUnsafeLaunchPath for %q, got %v", name, path, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// playtimeFilter restricts a library listing by total playtime in minutes.
+// A nil bound is open.
+type playtimeFilter struct {
+	Min *int
+	Max *int
+}
+
+// parsePlaytimeFilter reads ?min_playtime, ?max_playtime and the ?never_played=true shortcut.
+func parsePlaytimeFilter(q url.Values) (playtimeFilter, error) {
+	var f playtimeFilter
+
+	if raw := strings.TrimSpace(q.Get("never_played")); raw != "" {
+		neverPlayed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, errors.New("never_played must be true or false")
+		}
+		if neverPlayed {
+			zero := 0
+			f.Min, f.Max = &zero, &zero
+			if q.Get("min_playtime") != "" || q.Get("max_playtime") != "" {
+				return f, errors.New("never_played cannot be combined with min_playtime or max_playtime")
+			}
+			return f, nil
+		}
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  **int
+	}{{"min_playtime", &f.Min}, {"max_playtime", &f.Max}} {
+		raw := strings.TrimSpace(q.Get(bound.name))
+		if raw == "" {
+			continue
+		}
+		minutes, err := strconv.Atoi(raw)
+		if err != nil || minutes < 0 {
+			return f, errors.New(bound.name + " must be a non-negative number of minutes")
+		}
+		*bound.dst = &minutes
+	}
+
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return f, errors.New("min_playtime must not exceed max_playtime")
+	}
+	return f, nil
+}
+
+// matches reports whether a game with the given playtime passes the filter.
+func (f playtimeFilter) matches(minutes int) bool {
+	if f.Min != nil && minutes < *f.Min {
+		return false
+	}
+	if f.Max != nil && minutes > *f.Max {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestPlaytimeFilterNeverPlayed(t *testing.T) {
+	f, err := parsePlaytimeFilter(url.Values{"never_played": {"true"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !f.matches(0) || f.matches(1) {
+		t.Fatal("never_played should only match zero playtime")
+	}
+}
+
+func TestPlaytimeFilterRange(t *testing.T) {
+	f, err := parsePlaytimeFilter(url.Values{"min_playtime": {"60"}, "max_playtime": {"120"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for minutes, want := range map[int]bool{0: false, 59: false, 60: true, 120: true, 121: false} {
+		if got := f.matches(minutes); got != want {
+			t.Errorf("matches(%d) = %t, want %t", minutes, got, want)
+		}
+	}
+
+	open, err := parsePlaytimeFilter(url.Values{})
+	if err != nil || !open.matches(0) || !open.matches(100000) {
+		t.Fatalf("empty filter should match everything, err=%v", err)
+	}
+}
+
+func TestPlaytimeFilterRejectsInvalidInput(t *testing.T) {
+	for _, q := range []url.Values{
+		{"min_playtime": {"-1"}},
+		{"max_playtime": {"abc"}},
+		{"min_playtime": {"10"}, "max_playtime": {"5"}},
+		{"never_played": {"maybe"}},
+		{"never_played": {"true"}, "min_playtime": {"5"}},
+	} {
+		if _, err := parsePlaytimeFilter(q); err == nil {
+			t.Errorf("expected error for %v", q)
+		}
+	}
+}
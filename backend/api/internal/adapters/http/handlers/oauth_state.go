@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+const (
+	defaultOAuthStateTTL        = 10 * time.Minute
+	defaultOAuthStateMaxEntries = 10000
+)
+
+var (
+	ErrOAuthStateStoreFull = errors.New("oauth state store full")
+	// ErrOAuthStateProviderMismatch means a state issued for one provider was returned to another provider's callback.
+	ErrOAuthStateProviderMismatch = errors.New("oauth state issued for another provider")
+)
+
+// OAuthState is the server-side record for an issued OAuth/OpenID state value.
+type OAuthState struct {
+	Provider  string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// StateStore keeps issued OAuth state values so callbacks don't depend on
+// cookies surviving cross-origin redirects (e.g. the desktop login flow).
+type StateStore interface {
+	// Put records a state value for the given provider and optional linking user.
+	Put(ctx context.Context, state string, entry OAuthState) error
+	// Take returns and deletes a state value. Expired or unknown states report false.
+	Take(ctx context.Context, state string) (OAuthState, bool, error)
+}
+
+// MemoryStateStore is an in-process StateStore with a fixed TTL. Login and callback must reach
+// the same process, so it only suits single-replica deployments; see RepoStateStore.
+type MemoryStateStore struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]OAuthState
+	lastCleanup time.Time
+}
+
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	if ttl <= 0 {
+		ttl = defaultOAuthStateTTL
+	}
+	return &MemoryStateStore{
+		ttl:        ttl,
+		maxEntries: defaultOAuthStateMaxEntries,
+		now:        time.Now,
+		entries:    make(map[string]OAuthState),
+	}
+}
+
+func (s *MemoryStateStore) Put(_ context.Context, state string, entry OAuthState) error {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastCleanup.IsZero() || now.Sub(s.lastCleanup) > s.ttl || len(s.entries) >= s.maxEntries {
+		for key, existing := range s.entries {
+			if now.After(existing.ExpiresAt) {
+				delete(s.entries, key)
+			}
+		}
+		s.lastCleanup = now
+	}
+	if len(s.entries) >= s.maxEntries {
+		return ErrOAuthStateStoreFull
+	}
+
+	entry.ExpiresAt = now.Add(s.ttl)
+	s.entries[state] = entry
+	return nil
+}
+
+func (s *MemoryStateStore) Take(_ context.Context, state string) (OAuthState, bool, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[state]
+	delete(s.entries, state)
+	s.mu.Unlock()
+
+	if !ok || s.now().After(entry.ExpiresAt) {
+		return OAuthState{}, false, nil
+	}
+	return entry, true, nil
+}
+
+// RepoStateStore keeps state values in the database, so the login redirect and the callback
+// may be served by different API replicas. Like MemoryStateStore it refuses new states once
+// maxEntries unexpired ones are stored.
+type RepoStateStore struct {
+	repo       repo.OAuthStateRepo
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+func NewRepoStateStore(r repo.OAuthStateRepo, ttl time.Duration) *RepoStateStore {
+	if ttl <= 0 {
+		ttl = defaultOAuthStateTTL
+	}
+	return &RepoStateStore{repo: r, ttl: ttl, maxEntries: defaultOAuthStateMaxEntries, now: time.Now}
+}
+
+func (s *RepoStateStore) Put(ctx context.Context, state string, entry OAuthState) error {
+	now := s.now()
+	count, err := s.repo.CountOAuthStates(ctx, now.Unix())
+	if err != nil {
+		return err
+	}
+	if count >= s.maxEntries {
+		return ErrOAuthStateStoreFull
+	}
+	return s.repo.PutOAuthState(ctx, repo.OAuthStateRecord{
+		State:         state,
+		Provider:      entry.Provider,
+		UserID:        entry.UserID,
+		ExpiresAtUnix: now.Add(s.ttl).Unix(),
+	}, now.Unix())
+}
+
+func (s *RepoStateStore) Take(ctx context.Context, state string) (OAuthState, bool, error) {
+	rec, ok, err := s.repo.TakeOAuthState(ctx, state, s.now().Unix())
+	if err != nil || !ok {
+		return OAuthState{}, false, err
+	}
+	return OAuthState{Provider: rec.Provider, UserID: rec.UserID, ExpiresAt: time.Unix(rec.ExpiresAtUnix, 0)}, true, nil
+}
+
+// issueOAuthState creates a random state value and records it for provider.
+// The initiating user is recorded when the request is authenticated (account linking).
+func issueOAuthState(r *http.Request, store StateStore, provider string) (string, error) {
+	state, err := newStateToken()
+	if err != nil {
+		return "", err
+	}
+
+	entry := OAuthState{Provider: provider}
+	if user, ok := authmw.GetUserFromContext(r.Context()); ok {
+		entry.UserID = user.ID
+	}
+
+	if err := store.Put(r.Context(), state, entry); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// consumeOAuthState looks up and invalidates a state value returned to provider's callback.
+// A state minted for a different provider is consumed and rejected with ErrOAuthStateProviderMismatch.
+func consumeOAuthState(r *http.Request, store StateStore, provider, state string) (OAuthState, error) {
+	if state == "" {
+		return OAuthState{}, errors.New("empty state")
+	}
+
+	entry, ok, err := store.Take(r.Context(), state)
+	if err != nil {
+		return OAuthState{}, err
+	}
+	if !ok {
+		return OAuthState{}, errors.New("unknown or expired state")
+	}
+	if entry.Provider != provider {
+		return OAuthState{}, ErrOAuthStateProviderMismatch
+	}
+	return entry, nil
+}
+
+// writeOAuthStateError reports a rejected callback state.
+func writeOAuthStateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrOAuthStateProviderMismatch) {
+		http.Error(w, "invalid state", http.StatusUnauthorized)
+		return
+	}
+	http.Error(w, "invalid state", http.StatusBadRequest)
+}
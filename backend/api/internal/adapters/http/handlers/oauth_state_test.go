@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+func TestMemoryStateStoreIsSingleUse(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "abc", OAuthState{Provider: "steam", UserID: "user-1"}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	entry, ok, err := store.Take(ctx, "abc")
+	if err != nil || !ok {
+		t.Fatalf("expected stored state, got ok=%t err=%v", ok, err)
+	}
+	if entry.Provider != "steam" || entry.UserID != "user-1" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	if _, ok, _ := store.Take(ctx, "abc"); ok {
+		t.Fatal("expected state to be consumed after first use")
+	}
+}
+
+func TestMemoryStateStoreExpires(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "abc", OAuthState{Provider: "epic"}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := store.Take(ctx, "abc"); ok {
+		t.Fatal("expected expired state to be rejected")
+	}
+}
+
+func TestSteamCallbackRejectsUnknownState(t *testing.T) {
+	handler := NewSteamHandler("", "", "https://gamedivers.de", nil, nil)
+
+	req := httptest.NewRequest("GET", "/v1/steam/callback?state=unknown", nil)
+	w := httptest.NewRecorder()
+
+	handler.Callback(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestEpicCallbackRejectsReplayedState(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute)
+	handler := NewEpicHandler("", "", "", "https://gamedivers.de", store)
+
+	if err := store.Put(context.Background(), "used", OAuthState{Provider: "epic"}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, _, err := store.Take(context.Background(), "used"); err != nil {
+		t.Fatalf("take failed: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/v1/epic/callback?code=abc&state=used", nil)
+	w := httptest.NewRecorder()
+
+	handler.Callback(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestEpicCallbackRejectsSteamState(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute)
+	epic := NewEpicHandler("", "", "", "https://gamedivers.de", store)
+
+	state, err := issueOAuthState(httptest.NewRequest("GET", "/v1/steam/login", nil), store, "steam")
+	if err != nil {
+		t.Fatalf("issue state: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/v1/epic/callback?code=abc&state="+state, nil)
+	w := httptest.NewRecorder()
+
+	epic.Callback(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", w.Code)
+	}
+	// The mismatched attempt burns the state so it can't be retried on the right callback.
+	if _, ok, _ := store.Take(context.Background(), state); ok {
+		t.Fatal("expected mismatched state to be consumed")
+	}
+}
+
+// fakeStateRepo stands in for the shared database behind several API replicas.
+type fakeStateRepo struct {
+	repo.OAuthStateRepo
+	records map[string]repo.OAuthStateRecord
+}
+
+func (f *fakeStateRepo) PutOAuthState(_ context.Context, rec repo.OAuthStateRecord, _ int64) error {
+	f.records[rec.State] = rec
+	return nil
+}
+
+func (f *fakeStateRepo) TakeOAuthState(_ context.Context, state string, nowUnix int64) (*repo.OAuthStateRecord, bool, error) {
+	rec, ok := f.records[state]
+	delete(f.records, state)
+	if !ok || rec.ExpiresAtUnix < nowUnix {
+		return nil, false, nil
+	}
+	return &rec, true, nil
+}
+
+func (f *fakeStateRepo) CountOAuthStates(_ context.Context, nowUnix int64) (int, error) {
+	n := 0
+	for _, rec := range f.records {
+		if rec.ExpiresAtUnix >= nowUnix {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestRepoStateStoreSharesStateAcrossReplicas(t *testing.T) {
+	shared := &fakeStateRepo{records: map[string]repo.OAuthStateRecord{}}
+	loginPod := NewRepoStateStore(shared, time.Minute)
+	callbackPod := NewRepoStateStore(shared, time.Minute)
+	ctx := context.Background()
+
+	if err := loginPod.Put(ctx, "abc", OAuthState{Provider: "steam", UserID: "user-1"}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+
+	entry, ok, err := callbackPod.Take(ctx, "abc")
+	if err != nil || !ok || entry.Provider != "steam" || entry.UserID != "user-1" {
+		t.Fatalf("expected the other replica to see the state, got %+v ok=%t err=%v", entry, ok, err)
+	}
+	if _, ok, _ := loginPod.Take(ctx, "abc"); ok {
+		t.Fatal("expected state to be consumed after first use")
+	}
+}
+
+func TestRepoStateStoreRejectsPutWhenFull(t *testing.T) {
+	shared := &fakeStateRepo{records: map[string]repo.OAuthStateRecord{}}
+	store := NewRepoStateStore(shared, time.Minute)
+	store.maxEntries = 2
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, state := range []string{"a", "b"} {
+		if err := store.Put(ctx, state, OAuthState{Provider: "steam"}); err != nil {
+			t.Fatalf("put %s failed: %v", state, err)
+		}
+	}
+	if err := store.Put(ctx, "c", OAuthState{Provider: "steam"}); !errors.Is(err, ErrOAuthStateStoreFull) {
+		t.Fatalf("expected ErrOAuthStateStoreFull, got %v", err)
+	}
+
+	// Expired states no longer count against the cap.
+	now = now.Add(2 * time.Minute)
+	if err := store.Put(ctx, "c", OAuthState{Provider: "steam"}); err != nil {
+		t.Fatalf("expected put after expiry to succeed, got %v", err)
+	}
+}
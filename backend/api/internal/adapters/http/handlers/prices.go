@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -11,29 +13,49 @@ import (
 	"gamedivers.de/api/internal/ports/repo"
 )
 
+const (
+	defaultPriceHistoryDays = 90
+	maxPriceHistoryDays     = 730
+)
+
 type PriceHandler struct {
 	Pricing *service.PricingService
 	Repo    repo.Repo
+	// DefaultCountry is the store region used when the request has no ?cc=
+	DefaultCountry string
 }
 
-func (h *PriceHandler) GetSteamDEPrice(w http.ResponseWriter, r *http.Request) {
+// GetSteamPrice returns the stored Steam price for a region, refreshing it when stale
+// GET /v1/prices/steam/{appid}?cc=de&refresh=1
+func (h *PriceHandler) GetSteamPrice(w http.ResponseWriter, r *http.Request) {
+	if h.Pricing == nil || h.Repo == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Price lookups require persistence")
+		return
+	}
+
 	appid := chi.URLParam(r, "appid")
 	if appid == "" {
 		http.Error(w, "missing appid", http.StatusBadRequest)
 		return
 	}
 
+	cc, ok := h.priceCountry(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+
 	force := r.URL.Query().Get("refresh") == "1"
 
-	if err := h.Pricing.EnsureSteamDEPriceFresh(r.Context(), appid, force); err != nil {
-		logSafeError("ensure steam price failed", err)
+	if err := h.Pricing.EnsureSteamPriceFresh(r.Context(), appid, cc, force); err != nil {
+		logSafeError(r.Context(), "ensure steam price failed", err)
 		writeBadGateway(w)
 		return
 	}
 
-	row, found, err := h.Repo.GetPriceRow(r.Context(), "steam", appid, "de")
+	row, found, err := h.Repo.GetPriceRow(r.Context(), "steam", appid, cc)
 	if err != nil {
-		logSafeError("get price row failed", err)
+		logSafeError(r.Context(), "get price row failed", err)
 		writeInternalError(w)
 		return
 	}
@@ -43,7 +65,7 @@ func (h *PriceHandler) GetSteamDEPrice(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"store_id":         "steam",
 			"external_game_id": appid,
-			"cc":               "de",
+			"cc":               cc,
 			"note":             "no price data available",
 		})
 		return
@@ -59,16 +81,94 @@ func (h *PriceHandler) TrackSteamApp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cc, ok := h.priceCountry(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+
 	now := time.Now().Unix()
-	if err := h.Repo.TrackGame(r.Context(), "steam", appid, "de", now); err != nil {
-		logSafeError("track steam app failed", err)
+	if err := h.Repo.TrackGame(r.Context(), "steam", appid, cc, now); err != nil {
+		logSafeError(r.Context(), "track steam app failed", err)
 		writeInternalError(w)
 		return
 	}
 
 	if r.URL.Query().Get("prefetch") == "1" {
-		_ = h.Pricing.EnsureSteamDEPriceFresh(r.Context(), appid, true)
+		_ = h.Pricing.EnsureSteamPriceFresh(r.Context(), appid, cc, true)
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type priceHistoryResponse struct {
+	StoreID        string            `json:"store_id"`
+	ExternalGameID string            `json:"external_game_id"`
+	CC             string            `json:"cc"`
+	Points         []repo.PricePoint `json:"points"`
+}
+
+// GetPriceHistory returns the recorded price changes of a game, oldest first
+// GET /v1/prices/games/{gameId}/history?store=steam&cc=de&days=90
+func (h *PriceHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	if h.Repo == nil {
+		writeError(w, http.StatusServiceUnavailable, "persistence_unavailable", "Price history requires persistence")
+		return
+	}
+
+	gameID := chi.URLParam(r, "gameId")
+	if gameID == "" {
+		writeError(w, http.StatusBadRequest, "missing_game_id", "Game ID is required")
+		return
+	}
+
+	q := r.URL.Query()
+	storeID := strings.ToLower(strings.TrimSpace(q.Get("store")))
+	if storeID == "" {
+		storeID = "steam"
+	}
+	cc, ok := h.priceCountry(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+
+	days := defaultPriceHistoryDays
+	if raw := q.Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxPriceHistoryDays {
+			writeError(w, http.StatusBadRequest, "invalid_days", "days must be between 1 and "+strconv.Itoa(maxPriceHistoryDays))
+			return
+		}
+		days = parsed
+	}
+
+	since := time.Now().AddDate(0, 0, -days).Unix()
+	points, err := h.Repo.ListPriceHistory(r.Context(), storeID, gameID, cc, since)
+	if err != nil {
+		logSafeError(r.Context(), "list price history failed", err)
+		writeInternalError(w)
+		return
+	}
+	if points == nil {
+		points = []repo.PricePoint{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(priceHistoryResponse{
+		StoreID:        storeID,
+		ExternalGameID: gameID,
+		CC:             cc,
+		Points:         points,
+	})
+}
+
+// priceCountry resolves the lower-case store region from ?cc=, then the signed-in user's
+// stored country, then DefaultCountry. Prices are stored per region under lower-case codes.
+func (h *PriceHandler) priceCountry(r *http.Request) (string, bool) {
+	country, ok := requestCountry(r, r.URL.Query().Get("cc"), h.Repo, h.DefaultCountry)
+	if !ok {
+		return "", false
+	}
+	return strings.ToLower(country), true
+}
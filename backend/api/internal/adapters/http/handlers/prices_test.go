@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+type fakePriceHistoryRepo struct {
+	repo.Repo
+	points []repo.PricePoint
+
+	storeID, gameID, cc string
+	since               int64
+	country             string
+}
+
+func (f *fakePriceHistoryRepo) GetUser(_ context.Context, userID string) (*repo.User, error) {
+	return &repo.User{ID: userID, Country: f.country}, nil
+}
+
+func (f *fakePriceHistoryRepo) ListPriceHistory(_ context.Context, storeID, externalGameID, cc string, sinceUnix int64) ([]repo.PricePoint, error) {
+	f.storeID, f.gameID, f.cc, f.since = storeID, externalGameID, cc, sinceUnix
+	return f.points, nil
+}
+
+func servePriceHistory(h *PriceHandler, target string) *httptest.ResponseRecorder {
+	return servePriceHistoryRequest(h, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func servePriceHistoryRequest(h *PriceHandler, req *http.Request) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.Get("/v1/prices/games/{gameId}/history", h.GetPriceHistory)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetPriceHistory(t *testing.T) {
+	fake := &fakePriceHistoryRepo{points: []repo.PricePoint{
+		{Currency: "EUR", InitialCents: 1999, FinalCents: 1999, RecordedAtUnix: 100},
+		{Currency: "EUR", InitialCents: 1999, FinalCents: 999, DiscountPercent: 50, RecordedAtUnix: 200},
+	}}
+
+	w := servePriceHistory(&PriceHandler{Repo: fake}, "/v1/prices/games/220/history?store=Steam&cc=DE&days=30")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if fake.storeID != "steam" || fake.gameID != "220" || fake.cc != "de" {
+		t.Fatalf("unexpected lookup %q/%q/%q", fake.storeID, fake.gameID, fake.cc)
+	}
+	wantSince := time.Now().AddDate(0, 0, -30).Unix()
+	if diff := wantSince - fake.since; diff < 0 || diff > 5 {
+		t.Fatalf("expected since about %d, got %d", wantSince, fake.since)
+	}
+
+	var resp priceHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Points) != 2 || resp.Points[1].FinalCents != 999 {
+		t.Fatalf("unexpected points %+v", resp.Points)
+	}
+}
+
+func TestGetPriceHistoryEmptyIsArray(t *testing.T) {
+	w := servePriceHistory(&PriceHandler{Repo: &fakePriceHistoryRepo{}}, "/v1/prices/games/220/history")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if string(body["points"]) != "[]" {
+		t.Fatalf("expected empty points array, got %s", body["points"])
+	}
+}
+
+func TestGetPriceHistoryRejectsInvalidDays(t *testing.T) {
+	for _, days := range []string{"0", "-1", "abc", "100000"} {
+		w := servePriceHistory(&PriceHandler{Repo: &fakePriceHistoryRepo{}}, "/v1/prices/games/220/history?days="+days)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("days=%s: expected status 400, got %d", days, w.Code)
+		}
+	}
+}
+
+func TestGetPriceHistoryWithoutPersistence(t *testing.T) {
+	w := servePriceHistory(&PriceHandler{}, "/v1/prices/games/220/history")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+}
+
+func TestGetPriceHistoryUsesStoredCountry(t *testing.T) {
+	fake := &fakePriceHistoryRepo{country: "us"}
+	req := httptest.NewRequest(http.MethodGet, "/v1/prices/games/220/history", nil)
+	req = req.WithContext(context.WithValue(req.Context(), authmw.UserContextKey, &authmw.AuthenticatedUser{ID: "user-1"}))
+
+	w := servePriceHistoryRequest(&PriceHandler{Repo: fake, DefaultCountry: "DE"}, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if fake.cc != "us" {
+		t.Fatalf("expected the user's region us, got %q", fake.cc)
+	}
+}
@@ -1,58 +1,78 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
-	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+
 	authmw "gamedivers.de/api/internal/adapters/http/middleware"
 	"gamedivers.de/api/internal/adapters/stores/steam"
 	"gamedivers.de/api/internal/ports/repo"
+	"gamedivers.de/api/internal/ports/store"
 )
 
+// maxFriendsOwningQueries caps the friend libraries fetched per friends-owning request.
+const maxFriendsOwningQueries = 25
+
 type SteamHandler struct {
 	steamClient *steam.Client
-	repo        repo.Repo
-	frontendURL string
-	callbackURL string
+	// achievements fetches one game's achievements (steamClient.GetAchievements outside tests)
+	achievements func(ctx context.Context, steamID string, appID int) (*steam.AchievementSummary, error)
+	repo         repo.Repo
+	states       StateStore
+	frontendURL  string
+	callbackURL  string
+	// DefaultCountry is the store region used when a request names none (DEFAULT_COUNTRY).
+	DefaultCountry string
 }
 
-func NewSteamHandler(steamAPIKey, callbackURL, frontendOrigin string, repo repo.Repo) *SteamHandler {
+func NewSteamHandler(steamAPIKey, callbackURL, frontendOrigin string, repo repo.Repo, states StateStore) *SteamHandler {
+	if states == nil {
+		states = NewMemoryStateStore(defaultOAuthStateTTL)
+	}
+	client := steam.NewClient(steamAPIKey, callbackURL)
 	return &SteamHandler{
-		steamClient: steam.NewClient(steamAPIKey, callbackURL),
-		repo:        repo,
-		frontendURL: sanitizeFrontendOrigin(frontendOrigin),
-		callbackURL: sanitizeCallbackURL(callbackURL),
+		steamClient:  client,
+		achievements: client.GetAchievements,
+		repo:         repo,
+		states:       states,
+		frontendURL:  sanitizeFrontendOrigin(frontendOrigin),
+		callbackURL:  sanitizeCallbackURL(callbackURL),
 	}
 }
 
+// AppDetailsState reports the Steam appdetails breaker of the handler's client.
+func (h *SteamHandler) AppDetailsState() steam.BreakerState {
+	return h.steamClient.AppDetailsState()
+}
+
+// ShareAppDetailsBreaker makes c pause and resume appdetails calls together with the handler's client.
+func (h *SteamHandler) ShareAppDetailsBreaker(c *steam.Client) {
+	c.ShareAppDetailsBreaker(h.steamClient)
+}
+
 // LoginRedirect redirects to Steam OpenID login
 // GET /v1/steam/login
 func (h *SteamHandler) LoginRedirect(w http.ResponseWriter, r *http.Request) {
 	returnURL := h.resolveSteamCallbackURL(r)
 
-	state, err := newStateToken()
+	state, err := issueOAuthState(r, h.states, "steam")
 	if err != nil {
+		logSafeError(r.Context(), "steam state generation failed", err)
 		http.Error(w, "state generation failed", http.StatusInternalServerError)
 		return
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     "steam_state",
-		Value:    state,
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Secure:   isSecureRequest(r),
-	})
 
 	loginURL := h.steamClient.GetLoginURL(withState(returnURL, state))
 	http.Redirect(w, r, loginURL, http.StatusTemporaryRedirect)
@@ -67,34 +87,25 @@ func (h *SteamHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.verifyState(r.Form.Get("state"), r); err != nil {
-		http.Error(w, "invalid state", http.StatusBadRequest)
+	if _, err := consumeOAuthState(r, h.states, "steam", r.Form.Get("state")); err != nil {
+		writeOAuthStateError(w, err)
 		return
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     "steam_state",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		Secure:   isSecureRequest(r),
-		MaxAge:   -1,
-	})
 
 	// Verify the callback
 	steamID, err := h.steamClient.VerifyCallback(r.Form)
 	if err != nil {
-		logSafeError("steam auth verification failed", err)
+		logSafeError(r.Context(), "steam auth verification failed", err)
 		http.Error(w, "authentication failed", http.StatusUnauthorized)
 		return
 	}
 
-	log.Printf("Steam authentication successful for SteamID: %s", steamID)
+	authmw.Logger(r.Context()).Printf("Steam authentication successful for SteamID: %s", steamID)
 
 	// Get player profile
-	players, err := h.steamClient.GetPlayerSummaries([]string{steamID})
+	players, err := h.steamClient.GetPlayerSummaries(r.Context(), []string{steamID})
 	if err != nil || len(players) == 0 {
-		logSafeError("steam player summary failed", err)
+		logSafeError(r.Context(), "steam player summary failed", err)
 	}
 
 	// Store session (simplified - in production use proper session management)
@@ -108,7 +119,7 @@ func (h *SteamHandler) Callback(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetLibrary retrieves the authenticated user's Steam library
-// GET /v1/steam/library?steamid={steamid}
+// GET /v1/steam/library?steamid={steamid}[&never_played=true|&min_playtime=&max_playtime=]
 func (h *SteamHandler) GetLibrary(w http.ResponseWriter, r *http.Request) {
 	steamID := r.URL.Query().Get("steamid")
 	if steamID == "" {
@@ -116,19 +127,15 @@ func (h *SteamHandler) GetLibrary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	games, err := h.steamClient.GetOwnedGames(steamID)
+	playtime, err := parsePlaytimeFilter(r.URL.Query())
 	if err != nil {
-		logSafeError("steam library fetch failed", err)
-		msg := err.Error()
-		if strings.Contains(msg, "steam api error: 401") || strings.Contains(msg, "steam api error: 403") {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-			json.NewEncoder(w).Encode(map[string]any{
-				"error": "steam_profile_private",
-			})
-			return
-		}
-		http.Error(w, "failed to fetch library", http.StatusBadGateway)
+		writeError(w, http.StatusBadRequest, "invalid_filter", err.Error())
+		return
+	}
+
+	games, err := h.steamClient.GetOwnedGames(r.Context(), steamID)
+	if err != nil {
+		writeSteamFetchError(w, r, err, "steam library fetch failed", "failed to fetch library")
 		return
 	}
 
@@ -146,6 +153,10 @@ func (h *SteamHandler) GetLibrary(w http.ResponseWriter, r *http.Request) {
 
 	var response []GameResponse
 	for _, game := range games {
+		if !playtime.matches(game.PlaytimeForever) {
+			continue
+		}
+
 		imageURL := fmt.Sprintf("https://cdn.akamai.steamstatic.com/steam/apps/%d/header.jpg", game.AppID)
 		imageFallbackURL := ""
 		if game.ImgIconURL != "" {
@@ -164,12 +175,92 @@ func (h *SteamHandler) GetLibrary(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	// The desktop app polls on focus; let it revalidate instead of refetching an unchanged library.
+	writeJSONWithETag(w, r, response)
+}
+
+// GetFriendsOwning counts how many of the user's Steam friends own each game in their library
+// GET /v1/steam/friends-owning?steamid={steamid}
+func (h *SteamHandler) GetFriendsOwning(w http.ResponseWriter, r *http.Request) {
+	steamID := r.URL.Query().Get("steamid")
+	if steamID == "" {
+		http.Error(w, "missing steamid parameter", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.steamClient.CountFriendsOwning(r.Context(), steamID, maxFriendsOwningQueries)
+	if err != nil {
+		writeSteamFetchError(w, r, err, "steam friends owning failed", "failed to fetch friends' libraries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetProfile retrieves the user's Steam profile with their level and account creation time.
+// GET /v1/steam/profile?steamid={steamid}
+func (h *SteamHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	steamID := r.URL.Query().Get("steamid")
+	if steamID == "" {
+		http.Error(w, "missing steamid parameter", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.steamClient.GetProfile(r.Context(), steamID)
+	if err != nil {
+		writeSteamFetchError(w, r, err, "steam profile fetch failed", "failed to fetch steam profile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// GetRecentlyPlayed retrieves the games played in the last two weeks.
+// GET /v1/steam/recent?steamid={steamid}
+func (h *SteamHandler) GetRecentlyPlayed(w http.ResponseWriter, r *http.Request) {
+	steamID := r.URL.Query().Get("steamid")
+	if steamID == "" {
+		http.Error(w, "missing steamid parameter", http.StatusBadRequest)
+		return
+	}
+
+	games, err := h.steamClient.GetRecentlyPlayedGames(r.Context(), steamID)
+	if err != nil {
+		writeSteamFetchError(w, r, err, "steam recently played fetch failed", "failed to fetch recently played games")
+		return
+	}
+
+	type RecentGameResponse struct {
+		ID              string `json:"id"`
+		AppID           int    `json:"appId"`
+		Name            string `json:"name"`
+		Platform        string `json:"platform"`
+		Image           string `json:"image"`
+		Playtime2Weeks  int    `json:"playtime2Weeks"`
+		PlaytimeForever int    `json:"playtime"`
+	}
+
+	response := make([]RecentGameResponse, 0, len(games))
+	for _, game := range games {
+		response = append(response, RecentGameResponse{
+			ID:              fmt.Sprintf("%d", game.AppID),
+			AppID:           game.AppID,
+			Name:            game.Name,
+			Platform:        "steam",
+			Image:           fmt.Sprintf("https://cdn.akamai.steamstatic.com/steam/apps/%d/header.jpg", game.AppID),
+			Playtime2Weeks:  game.Playtime2Weeks,
+			PlaytimeForever: game.PlaytimeForever,
+		})
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 // GetWishlist retrieves the authenticated user's Steam wishlist.
-// GET /v1/steam/wishlist?steamid={steamid}
+// GET /v1/steam/wishlist?steamid={steamid}&sort_by=added_at|priority|best_price&limit=&offset=&cc=
 func (h *SteamHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
 	steamID := r.URL.Query().Get("steamid")
 	if steamID == "" {
@@ -177,13 +268,25 @@ func (h *SteamHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := h.steamClient.GetWishlist(steamID)
+	query, err := parseWishlistQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+	// Same region as the price endpoints: ?cc=, the user's stored country, then DefaultCountry.
+	cc, ok := requestCountry(r, r.URL.Query().Get("cc"), h.repo, h.DefaultCountry)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+
+	items, err := h.steamClient.GetWishlist(r.Context(), steamID)
 	if err != nil {
-		logSafeError("steam wishlist fetch failed", err)
 		msg := err.Error()
 		if errors.Is(err, steam.ErrSteamWishlistPrivate) ||
 			strings.Contains(msg, "wishlist api error: 401") ||
 			strings.Contains(msg, "wishlist api error: 403") {
+			logSafeError(r.Context(), "steam wishlist fetch failed", err)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusForbidden)
 			json.NewEncoder(w).Encode(map[string]any{
@@ -191,20 +294,123 @@ func (h *SteamHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
-		http.Error(w, "failed to fetch wishlist", http.StatusBadGateway)
+		writeSteamFetchError(w, r, err, "steam wishlist fetch failed", "failed to fetch wishlist")
+		return
+	}
+
+	response := make([]wishlistItemResponse, len(items))
+	ids := make([]string, len(items))
+	for i, item := range items {
+		response[i].WishlistItem = item
+		ids[i] = strconv.Itoa(item.AppID)
+	}
+
+	// Attach stored prices when persistence is on; items without one sort last by best_price.
+	if h.repo != nil && len(ids) > 0 {
+		prices, err := h.repo.ListCurrentPrices(r.Context(), "steam", strings.ToLower(cc), ids)
+		if err != nil {
+			logSafeError(r.Context(), "wishlist price lookup failed", err)
+		}
+		for i := range response {
+			if cents, ok := prices[ids[i]]; ok {
+				response[i].FinalCents = &cents
+			}
+		}
+	}
+
+	sortWishlist(response, query.SortBy)
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(response)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(query.page(response))
+}
+
+// GetAchievements retrieves achievement progress for one Steam game. It only reads;
+// SyncAchievements stores the counts for the signed-in user.
+// GET /v1/steam/library/{appid}/achievements?steamid={steamid}
+func (h *SteamHandler) GetAchievements(w http.ResponseWriter, r *http.Request) {
+	summary, _, ok := h.fetchAchievements(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// SyncAchievements fetches achievement progress for one Steam game and stores the counts
+// as the signed-in user's own, so the library can show "24/50 achievements".
+// POST /v1/steam/library/{appid}/achievements/sync?steamid={steamid}
+func (h *SteamHandler) SyncAchievements(w http.ResponseWriter, r *http.Request) {
+	user, ok := authmw.GetUserFromContext(r.Context())
+	if !ok || strings.TrimSpace(user.ID) == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	summary, appID, ok := h.fetchAchievements(w, r)
+	if !ok {
 		return
 	}
 
+	if h.repo != nil {
+		now := time.Now().Unix()
+		if err := h.repo.UpsertUser(r.Context(), user.ID, now); err != nil {
+			logSafeError(r.Context(), "upsert user failed during achievements sync", err)
+			writeInternalError(w)
+			return
+		}
+		if err := h.repo.UpsertAchievementCounts(r.Context(), repo.UpsertAchievementParams{
+			UserID:         user.ID,
+			StoreID:        "steam",
+			ExternalGameID: strconv.Itoa(appID),
+			Unlocked:       summary.Unlocked,
+			Total:          summary.Total,
+			UpdatedAtUnix:  now,
+		}); err != nil {
+			logSafeError(r.Context(), "persist steam achievements failed", err)
+			writeInternalError(w)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(items)
+	json.NewEncoder(w).Encode(summary)
 }
 
+// fetchAchievements validates ?steamid= and {appid} and fetches the summary, writing the
+// error response itself when it reports false.
+func (h *SteamHandler) fetchAchievements(w http.ResponseWriter, r *http.Request) (*steam.AchievementSummary, int, bool) {
+	steamID := r.URL.Query().Get("steamid")
+	if steamID == "" {
+		http.Error(w, "missing steamid parameter", http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	appID, err := strconv.Atoi(chi.URLParam(r, "appid"))
+	if err != nil || appID <= 0 {
+		http.Error(w, "invalid appid: must be numeric", http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	summary, err := h.achievements(r.Context(), steamID, appID)
+	if err != nil {
+		writeSteamFetchError(w, r, err, "steam achievements fetch failed", "failed to fetch achievements")
+		return nil, 0, false
+	}
+	return summary, appID, true
+}
+
+// wishlistSyncBatchSize is how many wishlist games are stored per multi-row insert.
+const wishlistSyncBatchSize = 500
+
 type syncSteamWishlistRequest struct {
 	AppIDs []int `json:"appIds"`
 }
 
-// SyncWishlistToWatchlist stores steam wishlist app IDs in the backend user watchlist.
-// POST /v1/steam/wishlist/sync
+// SyncWishlistToWatchlist stores steam wishlist app IDs in the backend user watchlist,
+// in the ?cc= region, else the user's stored country, else DefaultCountry.
+// POST /v1/steam/wishlist/sync?cc=
 func (h *SteamHandler) SyncWishlistToWatchlist(w http.ResponseWriter, r *http.Request) {
 	user, ok := authmw.GetUserFromContext(r.Context())
 	if !ok || strings.TrimSpace(user.ID) == "" {
@@ -217,6 +423,13 @@ func (h *SteamHandler) SyncWishlistToWatchlist(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	cc, ok := requestCountry(r, r.URL.Query().Get("cc"), h.repo, h.DefaultCountry)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+	cc = strings.ToLower(cc)
+
 	var payload syncSteamWishlistRequest
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -225,7 +438,7 @@ func (h *SteamHandler) SyncWishlistToWatchlist(w http.ResponseWriter, r *http.Re
 
 	now := time.Now().Unix()
 	if err := h.repo.UpsertUser(r.Context(), user.ID, now); err != nil {
-		logSafeError("upsert user failed during wishlist sync", err)
+		logSafeError(r.Context(), "upsert user failed during wishlist sync", err)
 		writeInternalError(w)
 		return
 	}
@@ -238,20 +451,31 @@ func (h *SteamHandler) SyncWishlistToWatchlist(w http.ResponseWriter, r *http.Re
 		unique[appID] = struct{}{}
 	}
 
-	added := 0
+	appIDs := make([]string, 0, len(unique))
 	for appID := range unique {
-		appIDStr := strconv.Itoa(appID)
-		if err := h.repo.AddWatch(r.Context(), user.ID, "steam", appIDStr, "de", now); err != nil {
-			logSafeError("add watch failed during wishlist sync", err)
-			writeInternalError(w)
-			return
+		appIDs = append(appIDs, strconv.Itoa(appID))
+	}
+	sort.Strings(appIDs)
+
+	// Each batch commits on its own, so a cancelled or failed sync keeps the batches already stored.
+	added := 0
+	for start := 0; start < len(appIDs); start += wishlistSyncBatchSize {
+		end := min(start+wishlistSyncBatchSize, len(appIDs))
+		err := r.Context().Err()
+		if err == nil {
+			err = h.repo.AddWatchBatch(r.Context(), user.ID, "steam", appIDs[start:end], cc, now)
 		}
-		if err := h.repo.TrackGame(r.Context(), "steam", appIDStr, "de", now); err != nil {
-			logSafeError("track game failed during wishlist sync", err)
-			writeInternalError(w)
+		if err != nil {
+			logSafeError(r.Context(), "add watch batch failed during wishlist sync", err)
+			if added == 0 {
+				writeInternalError(w)
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "sync_incomplete",
+				fmt.Sprintf("Synced %d of %d wishlist games", added, len(appIDs)))
 			return
 		}
-		added++
+		added = end
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -269,10 +493,9 @@ func (h *SteamHandler) SyncLibrary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	games, err := h.steamClient.GetOwnedGames(steamID)
+	games, err := h.steamClient.GetOwnedGames(r.Context(), steamID)
 	if err != nil {
-		logSafeError("steam sync fetch failed", err)
-		http.Error(w, "failed to fetch library", http.StatusInternalServerError)
+		writeSteamFetchError(w, r, err, "steam sync fetch failed", "failed to fetch library")
 		return
 	}
 
@@ -288,6 +511,33 @@ func (h *SteamHandler) SyncLibrary(w http.ResponseWriter, r *http.Request) {
 
 // --- helpers ---
 
+// writeSteamFetchError logs a failed Steam call and answers it: 501 when Steam is not
+// configured, 403 for a private profile, 404 for an unknown SteamID and 502 otherwise.
+func writeSteamFetchError(w http.ResponseWriter, r *http.Request, err error, logMsg, message string) {
+	logSafeError(r.Context(), logMsg, err)
+	switch {
+	case errors.Is(err, store.ErrProviderNotConfigured):
+		writeProviderNotConfigured(w, "Steam")
+	case errors.Is(err, steam.ErrSteamProfilePrivate):
+		writeSteamProfilePrivate(w)
+	case errors.Is(err, steam.ErrPlayerNotFound):
+		writeError(w, http.StatusNotFound, "steam_player_not_found", "No Steam profile exists for this SteamID")
+	default:
+		http.Error(w, message, http.StatusBadGateway)
+	}
+}
+
+// writeSteamProfilePrivate tells the client that Steam refused to share the library,
+// so it is not mistaken for an empty one.
+func writeSteamProfilePrivate(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":   "steam_profile_private",
+		"message": "Steam profile or game details are private. Set \"Game details\" to public in your Steam privacy settings and try again.",
+	})
+}
+
 func newStateToken() (string, error) {
 	var buf [32]byte
 	if _, err := rand.Read(buf[:]); err != nil {
@@ -307,20 +557,6 @@ func withState(returnURL, state string) string {
 	return u.String()
 }
 
-func (h *SteamHandler) verifyState(state string, r *http.Request) error {
-	if state == "" {
-		return errors.New("empty state")
-	}
-	cookie, err := r.Cookie("steam_state")
-	if err != nil {
-		return err
-	}
-	if subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
-		return errors.New("state mismatch")
-	}
-	return nil
-}
-
 func sanitizeFrontendOrigin(origin string) string {
 	if origin == "" {
 		return ""
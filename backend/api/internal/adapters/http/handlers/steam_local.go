@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// steamAppStateFullyInstalled is the StateFlags bit Steam sets once an app is completely installed.
+const steamAppStateFullyInstalled = 4
+
+// vdfNode is a parsed Valve KeyValues (VDF/ACF) object. Values are either string or vdfNode.
+type vdfNode map[string]any
+
+// parseVDF parses the text KeyValues format used by libraryfolders.vdf and appmanifest_*.acf.
+func parseVDF(data string) (vdfNode, error) {
+	tokens, err := tokenizeVDF(data)
+	if err != nil {
+		return nil, err
+	}
+	node, _, err := parseVDFObject(tokens, 0, false)
+	return node, err
+}
+
+func parseVDFObject(tokens []vdfToken, pos int, nested bool) (vdfNode, int, error) {
+	node := vdfNode{}
+	for pos < len(tokens) {
+		tok := tokens[pos]
+		if tok.brace == '}' {
+			if !nested {
+				return nil, pos, errors.New("vdf: unexpected }")
+			}
+			return node, pos + 1, nil
+		}
+		if tok.brace != 0 {
+			return nil, pos, errors.New("vdf: expected key")
+		}
+		if pos+1 >= len(tokens) {
+			return nil, pos, errors.New("vdf: missing value")
+		}
+
+		key := tok.text
+		next := tokens[pos+1]
+		switch next.brace {
+		case '{':
+			child, end, err := parseVDFObject(tokens, pos+2, true)
+			if err != nil {
+				return nil, end, err
+			}
+			node[key] = child
+			pos = end
+		case 0:
+			node[key] = next.text
+			pos += 2
+		default:
+			return nil, pos, errors.New("vdf: unexpected }")
+		}
+	}
+	if nested {
+		return nil, pos, errors.New("vdf: missing }")
+	}
+	return node, pos, nil
+}
+
+type vdfToken struct {
+	text  string
+	brace byte
+}
+
+func tokenizeVDF(data string) ([]vdfToken, error) {
+	var tokens []vdfToken
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '{' || c == '}':
+			tokens = append(tokens, vdfToken{brace: c})
+			i++
+		case c == '"':
+			var sb strings.Builder
+			i++
+			for ; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' && i+1 < len(data) {
+					i++
+				}
+				sb.WriteByte(data[i])
+			}
+			if i >= len(data) {
+				return nil, errors.New("vdf: unterminated string")
+			}
+			tokens = append(tokens, vdfToken{text: sb.String()})
+			i++
+		default:
+			start := i
+			for i < len(data) && !strings.ContainsRune(" \t\r\n{}\"", rune(data[i])) {
+				i++
+			}
+			tokens = append(tokens, vdfToken{text: data[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+// lookup returns a child by case-insensitive key, since Valve files mix casing between versions.
+func (n vdfNode) lookup(key string) (any, bool) {
+	if v, ok := n[key]; ok {
+		return v, true
+	}
+	for k, v := range n {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (n vdfNode) str(key string) string {
+	v, _ := n.lookup(key)
+	s, _ := v.(string)
+	return s
+}
+
+func (n vdfNode) child(key string) vdfNode {
+	v, _ := n.lookup(key)
+	c, _ := v.(vdfNode)
+	return c
+}
+
+// defaultSteamRoots are the usual Steam client install locations per OS.
+func defaultSteamRoots() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		return []string{
+			filepath.Join("C:\\", "Program Files (x86)", "Steam"),
+			filepath.Join("C:\\", "Program Files", "Steam"),
+		}
+	case "darwin":
+		return []string{filepath.Join(home, "Library", "Application Support", "Steam")}
+	default:
+		return []string{
+			filepath.Join(home, ".steam", "steam"),
+			filepath.Join(home, ".local", "share", "Steam"),
+		}
+	}
+}
+
+// steamLibraryFolders returns every Steam library folder listed in libraryfolders.vdf,
+// including the Steam root itself.
+func steamLibraryFolders(steamRoot string) []string {
+	folders := []string{steamRoot}
+
+	data, err := os.ReadFile(filepath.Join(steamRoot, "steamapps", "libraryfolders.vdf"))
+	if err != nil {
+		return folders
+	}
+	parsed, err := parseVDF(string(data))
+	if err != nil {
+		return folders
+	}
+
+	libraries := parsed.child("libraryfolders")
+	for key, value := range libraries {
+		if _, err := strconv.Atoi(key); err != nil {
+			continue
+		}
+		switch v := value.(type) {
+		case vdfNode:
+			// Current format: "0" { "path" "..." "apps" { ... } }
+			if path := v.str("path"); path != "" {
+				folders = append(folders, path)
+			}
+		case string:
+			// Legacy format: "1" "D:\\SteamLibrary"
+			folders = append(folders, v)
+		}
+	}
+	return folders
+}
+
+// scanInstalledSteamGames reads appmanifest_*.acf files from all Steam libraries.
+// Missing Steam installations are skipped.
+func scanInstalledSteamGames(steamRoots []string) []InstalledGame {
+	seenFolders := map[string]struct{}{}
+	seenApps := map[string]struct{}{}
+	var games []InstalledGame
+
+	for _, root := range steamRoots {
+		if _, err := os.Stat(filepath.Join(root, "steamapps")); err != nil {
+			continue
+		}
+
+		for _, folder := range steamLibraryFolders(root) {
+			folder = filepath.Clean(folder)
+			if _, ok := seenFolders[folder]; ok {
+				continue
+			}
+			seenFolders[folder] = struct{}{}
+
+			manifests, _ := filepath.Glob(filepath.Join(folder, "steamapps", "appmanifest_*.acf"))
+			for _, manifestPath := range manifests {
+				data, err := os.ReadFile(manifestPath)
+				if err != nil {
+					continue
+				}
+				parsed, err := parseVDF(string(data))
+				if err != nil {
+					continue
+				}
+
+				state := parsed.child("AppState")
+				appID := state.str("appid")
+				if appID == "" {
+					continue
+				}
+				if _, ok := seenApps[appID]; ok {
+					continue
+				}
+				flags, _ := strconv.Atoi(state.str("StateFlags"))
+				if flags&steamAppStateFullyInstalled == 0 {
+					continue
+				}
+
+				seenApps[appID] = struct{}{}
+				games = append(games, InstalledGame{
+					ID:          appID,
+					Name:        state.str("name"),
+					Platform:    "steam",
+					InstallPath: filepath.Join(folder, "steamapps", "common", state.str("installdir")),
+				})
+			}
+		}
+	}
+	return games
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"gamedivers.de/api/internal/adapters/stores/steam"
+	"gamedivers.de/api/internal/ports/store"
 )
 
 func TestResolveSteamCallbackURLUsesConfiguredValue(t *testing.T) {
-	h := NewSteamHandler("", "https://gamedivers.de/api/v1/steam/callback", "https://gamedivers.de", nil)
+	h := NewSteamHandler("", "https://gamedivers.de/api/v1/steam/callback", "https://gamedivers.de", nil, nil)
 	req := httptest.NewRequest("GET", "https://gamedivers.de/api/v1/steam/login", nil)
 
 	got := h.resolveSteamCallbackURL(req)
@@ -17,7 +23,7 @@ func TestResolveSteamCallbackURLUsesConfiguredValue(t *testing.T) {
 }
 
 func TestResolveSteamCallbackURLDerivesFromRequestPath(t *testing.T) {
-	h := NewSteamHandler("", "", "https://gamedivers.de", nil)
+	h := NewSteamHandler("", "", "https://gamedivers.de", nil, nil)
 	req := httptest.NewRequest("GET", "https://gamedivers.de/api/v1/steam/login", nil)
 
 	got := h.resolveSteamCallbackURL(req)
@@ -27,3 +33,21 @@ func TestResolveSteamCallbackURLDerivesFromRequestPath(t *testing.T) {
 	}
 }
 
+
+func TestWriteSteamFetchError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("owned games: %w", store.ErrProviderNotConfigured), http.StatusNotImplemented},
+		{steam.ErrSteamProfilePrivate, http.StatusForbidden},
+		{steam.ErrPlayerNotFound, http.StatusNotFound},
+		{errors.New("steam api error: 500"), http.StatusBadGateway},
+	} {
+		w := httptest.NewRecorder()
+		writeSteamFetchError(w, httptest.NewRequest(http.MethodGet, "/v1/steam/library", nil), tc.err, "steam library fetch failed", "failed to fetch library")
+		if w.Code != tc.want {
+			t.Errorf("%v: expected %d, got %d", tc.err, tc.want, w.Code)
+		}
+	}
+}
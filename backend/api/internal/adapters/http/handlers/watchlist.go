@@ -15,29 +15,35 @@ func (h *PriceHandler) AddSteamWatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cc, ok := h.priceCountry(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+
 	now := time.Now().Unix()
 
 	if err := h.Repo.UpsertUser(r.Context(), userID, now); err != nil {
-		logSafeError("upsert user failed", err)
+		logSafeError(r.Context(), "upsert user failed", err)
 		writeInternalError(w)
 		return
 	}
 
-	if err := h.Repo.AddWatch(r.Context(), userID, "steam", appid, "de", now); err != nil {
-		logSafeError("add steam watch failed", err)
+	if err := h.Repo.AddWatch(r.Context(), userID, "steam", appid, cc, now); err != nil {
+		logSafeError(r.Context(), "add steam watch failed", err)
 		writeInternalError(w)
 		return
 	}
 
-	if err := h.Repo.TrackGame(r.Context(), "steam", appid, "de", now); err != nil {
-		logSafeError("track watched game failed", err)
+	if err := h.Repo.TrackGame(r.Context(), "steam", appid, cc, now); err != nil {
+		logSafeError(r.Context(), "track watched game failed", err)
 		writeInternalError(w)
 		return
 	}
 
 	if r.URL.Query().Get("prefetch") == "1" {
-		if err := h.Pricing.EnsureSteamDEPriceFresh(r.Context(), appid, true); err != nil {
-			logSafeError("prefetch steam price failed", err)
+		if err := h.Pricing.EnsureSteamPriceFresh(r.Context(), appid, cc, true); err != nil {
+			logSafeError(r.Context(), "prefetch steam price failed", err)
 			writeBadGateway(w)
 			return
 		}
@@ -54,8 +60,14 @@ func (h *PriceHandler) RemoveSteamWatch(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.Repo.RemoveWatch(r.Context(), userID, "steam", appid, "de"); err != nil {
-		logSafeError("remove steam watch failed", err)
+	cc, ok := h.priceCountry(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_country", "cc must be an ISO 3166-1 alpha-2 country code")
+		return
+	}
+
+	if err := h.Repo.RemoveWatch(r.Context(), userID, "steam", appid, cc); err != nil {
+		logSafeError(r.Context(), "remove steam watch failed", err)
 		writeInternalError(w)
 		return
 	}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gamedivers.de/api/internal/adapters/stores/steam"
+)
+
+// maxWishlistPageSize bounds ?limit on wishlist listings.
+const maxWishlistPageSize = 500
+
+const (
+	wishlistSortAddedAt   = "added_at"
+	wishlistSortPriority  = "priority"
+	wishlistSortBestPrice = "best_price"
+)
+
+// wishlistQuery holds the sort and page requested for a wishlist listing.
+// A zero Limit returns everything from Offset on.
+type wishlistQuery struct {
+	SortBy string
+	Limit  int
+	Offset int
+}
+
+// wishlistItemResponse is a wishlist entry with its stored current price, when known.
+type wishlistItemResponse struct {
+	steam.WishlistItem
+	FinalCents *int64 `json:"finalCents,omitempty"`
+}
+
+// parseWishlistQuery reads ?sort_by, ?limit and ?offset.
+func parseWishlistQuery(q url.Values) (wishlistQuery, error) {
+	query := wishlistQuery{SortBy: wishlistSortAddedAt}
+
+	switch sortBy := strings.TrimSpace(q.Get("sort_by")); sortBy {
+	case "":
+	case wishlistSortAddedAt, wishlistSortPriority, wishlistSortBestPrice:
+		query.SortBy = sortBy
+	default:
+		return query, errors.New("sort_by must be one of added_at, priority, best_price")
+	}
+
+	if raw := strings.TrimSpace(q.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxWishlistPageSize {
+			return query, errors.New("limit must be between 1 and " + strconv.Itoa(maxWishlistPageSize))
+		}
+		query.Limit = limit
+	}
+	if raw := strings.TrimSpace(q.Get("offset")); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return query, errors.New("offset must be a non-negative number")
+		}
+		query.Offset = offset
+	}
+
+	return query, nil
+}
+
+// sortWishlist orders items in place. Ties fall back to the newest addition, then the app ID.
+// Unranked items (priority 0) and items without a known price sort last.
+func sortWishlist(items []wishlistItemResponse, sortBy string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		switch sortBy {
+		case wishlistSortPriority:
+			if a.Priority != b.Priority {
+				if a.Priority == 0 || b.Priority == 0 {
+					return b.Priority == 0
+				}
+				return a.Priority < b.Priority
+			}
+		case wishlistSortBestPrice:
+			if (a.FinalCents == nil) != (b.FinalCents == nil) {
+				return b.FinalCents == nil
+			}
+			if a.FinalCents != nil && *a.FinalCents != *b.FinalCents {
+				return *a.FinalCents < *b.FinalCents
+			}
+		}
+		if a.Added != b.Added {
+			return a.Added > b.Added
+		}
+		return a.AppID < b.AppID
+	})
+}
+
+// page returns the slice of items selected by the query's limit and offset.
+func (q wishlistQuery) page(items []wishlistItemResponse) []wishlistItemResponse {
+	if q.Offset >= len(items) {
+		return []wishlistItemResponse{}
+	}
+	items = items[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(items) {
+		items = items[:q.Limit]
+	}
+	return items
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"gamedivers.de/api/internal/adapters/stores/steam"
+)
+
+func testWishlist() []wishlistItemResponse {
+	price := func(cents int64) *int64 { return &cents }
+	return []wishlistItemResponse{
+		{WishlistItem: steam.WishlistItem{AppID: 10, Added: 100, Priority: 2}, FinalCents: price(999)},
+		{WishlistItem: steam.WishlistItem{AppID: 20, Added: 300, Priority: 0}},
+		{WishlistItem: steam.WishlistItem{AppID: 30, Added: 200, Priority: 1}, FinalCents: price(199)},
+		{WishlistItem: steam.WishlistItem{AppID: 40, Added: 400, Priority: 3}, FinalCents: price(999)},
+	}
+}
+
+func wishlistAppIDs(items []wishlistItemResponse) []int {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.AppID
+	}
+	return ids
+}
+
+func assertAppIDs(t *testing.T, got []wishlistItemResponse, want ...int) {
+	t.Helper()
+	ids := wishlistAppIDs(got)
+	if len(ids) != len(want) {
+		t.Fatalf("expected app ids %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("expected app ids %v, got %v", want, ids)
+		}
+	}
+}
+
+func TestSortWishlist(t *testing.T) {
+	for _, tc := range []struct {
+		sortBy string
+		want   []int
+	}{
+		{wishlistSortAddedAt, []int{40, 20, 30, 10}},
+		// Unranked (priority 0) goes last.
+		{wishlistSortPriority, []int{30, 10, 40, 20}},
+		// Equal prices fall back to the newest addition; unknown prices go last.
+		{wishlistSortBestPrice, []int{30, 40, 10, 20}},
+	} {
+		items := testWishlist()
+		sortWishlist(items, tc.sortBy)
+		t.Run(tc.sortBy, func(t *testing.T) { assertAppIDs(t, items, tc.want...) })
+	}
+}
+
+func TestWishlistPage(t *testing.T) {
+	items := testWishlist()
+	sortWishlist(items, wishlistSortAddedAt)
+
+	assertAppIDs(t, wishlistQuery{Limit: 2}.page(items), 40, 20)
+	assertAppIDs(t, wishlistQuery{Limit: 2, Offset: 2}.page(items), 30, 10)
+	assertAppIDs(t, wishlistQuery{Limit: 10, Offset: 3}.page(items), 10)
+	assertAppIDs(t, wishlistQuery{Offset: 1}.page(items), 20, 30, 10)
+	if got := (wishlistQuery{Offset: 4}).page(items); got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty page past the end, got %v", got)
+	}
+}
+
+func TestParseWishlistQuery(t *testing.T) {
+	query, err := parseWishlistQuery(url.Values{"sort_by": {"best_price"}, "limit": {"50"}, "offset": {"100"}})
+	if err != nil || query.SortBy != wishlistSortBestPrice || query.Limit != 50 || query.Offset != 100 {
+		t.Fatalf("unexpected query %+v (%v)", query, err)
+	}
+
+	query, err = parseWishlistQuery(url.Values{})
+	if err != nil || query.SortBy != wishlistSortAddedAt || query.Limit != 0 || query.Offset != 0 {
+		t.Fatalf("unexpected default query %+v (%v)", query, err)
+	}
+
+	for _, invalid := range []url.Values{
+		{"sort_by": {"price"}},
+		{"limit": {"0"}},
+		{"limit": {"501"}},
+		{"offset": {"-1"}},
+		{"offset": {"x"}},
+	} {
+		if _, err := parseWishlistQuery(invalid); err == nil {
+			t.Errorf("expected %v to be rejected", invalid)
+		}
+	}
+}
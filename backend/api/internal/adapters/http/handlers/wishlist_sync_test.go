@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+// fakeWatchRepo implements only what the wishlist sync uses; other methods panic via the nil embed.
+type fakeWatchRepo struct {
+	repo.Repo
+	batches   [][]string
+	regions   []string
+	failAfter int
+	country   string
+}
+
+func (f *fakeWatchRepo) UpsertUser(context.Context, string, int64) error { return nil }
+
+func (f *fakeWatchRepo) GetUser(_ context.Context, userID string) (*repo.User, error) {
+	return &repo.User{ID: userID, Country: f.country}, nil
+}
+
+func (f *fakeWatchRepo) AddWatchBatch(_ context.Context, _, _ string, ids []string, cc string, _ int64) error {
+	if f.failAfter > 0 && len(f.batches) >= f.failAfter {
+		return errors.New("db down")
+	}
+	f.batches = append(f.batches, append([]string(nil), ids...))
+	f.regions = append(f.regions, cc)
+	return nil
+}
+
+func wishlistSyncRequest(t *testing.T, appIDs []int) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(syncSteamWishlistRequest{AppIDs: appIDs})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	req := httptest.NewRequest("POST", "/v1/steam/wishlist/sync", strings.NewReader(string(body)))
+	return req.WithContext(context.WithValue(req.Context(), authmw.UserContextKey, &authmw.AuthenticatedUser{ID: "user-1"}))
+}
+
+func TestSyncWishlistToWatchlistBatchesInserts(t *testing.T) {
+	appIDs := make([]int, 0, 502)
+	for i := 1; i <= 500; i++ {
+		appIDs = append(appIDs, i)
+	}
+	appIDs = append(appIDs, 1, -5) // duplicates and invalid ids are dropped
+
+	fake := &fakeWatchRepo{}
+	handler := NewSteamHandler("", "", "https://gamedivers.de", fake, nil)
+
+	w := httptest.NewRecorder()
+	handler.SyncWishlistToWatchlist(w, wishlistSyncRequest(t, appIDs))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if len(fake.batches) != 1 || len(fake.batches[0]) != 500 {
+		t.Fatalf("expected a single batched insert of 500 ids, got %d batches", len(fake.batches))
+	}
+
+	var resp map[string]int
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || resp["synced"] != 500 {
+		t.Fatalf("expected synced=500, got %v (err %v)", resp, err)
+	}
+}
+
+func TestSyncWishlistToWatchlistReportsPartialProgress(t *testing.T) {
+	appIDs := make([]int, 0, 2*wishlistSyncBatchSize)
+	for i := 1; i <= 2*wishlistSyncBatchSize; i++ {
+		appIDs = append(appIDs, i)
+	}
+
+	fake := &fakeWatchRepo{failAfter: 1}
+	handler := NewSteamHandler("", "", "https://gamedivers.de", fake, nil)
+
+	w := httptest.NewRecorder()
+	handler.SyncWishlistToWatchlist(w, wishlistSyncRequest(t, appIDs))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Error != "sync_incomplete" || !strings.Contains(resp.Message, "500 of 1000") {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
+func TestSyncWishlistToWatchlistUsesDefaultCountry(t *testing.T) {
+	fake := &fakeWatchRepo{}
+	handler := NewSteamHandler("", "", "https://gamedivers.de", fake, nil)
+	handler.DefaultCountry = "US"
+
+	w := httptest.NewRecorder()
+	handler.SyncWishlistToWatchlist(w, wishlistSyncRequest(t, []int{620}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if len(fake.regions) != 1 || fake.regions[0] != "us" {
+		t.Fatalf("expected the watch in region us, got %v", fake.regions)
+	}
+}
+
+func TestSyncWishlistToWatchlistUsesStoredCountry(t *testing.T) {
+	fake := &fakeWatchRepo{country: "us"}
+	handler := NewSteamHandler("", "", "https://gamedivers.de", fake, nil)
+	handler.DefaultCountry = "DE"
+
+	w := httptest.NewRecorder()
+	handler.SyncWishlistToWatchlist(w, wishlistSyncRequest(t, []int{620}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if len(fake.regions) != 1 || fake.regions[0] != "us" {
+		t.Fatalf("expected the watch in the user's region us, got %v", fake.regions)
+	}
+}
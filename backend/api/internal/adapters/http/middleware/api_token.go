@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+// APITokenPrefix marks personal access tokens so they can be told apart from Keycloak JWTs.
+const APITokenPrefix = "aio_pat_"
+
+// Scopes that can be granted to personal access tokens
+const (
+	ScopeLibraryRead  = "library:read"
+	ScopeWishlistRead = "wishlist:read"
+	ScopePricesRead   = "prices:read"
+)
+
+// APITokenScopes lists every scope a personal access token may be granted.
+var APITokenScopes = []string{ScopeLibraryRead, ScopeWishlistRead, ScopePricesRead}
+
+// APITokenStore is the subset of the repository needed to authenticate personal access tokens.
+type APITokenStore interface {
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*repo.APIToken, bool, error)
+	TouchAPIToken(ctx context.Context, tokenID string, nowUnix int64) error
+}
+
+// HashAPIToken returns the hex encoded SHA-256 of a plaintext token as stored in the database.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsValidAPITokenScope reports whether scope can be granted to a personal access token.
+func IsValidAPITokenScope(scope string) bool {
+	for _, known := range APITokenScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the user may use the given scope.
+// Session (JWT) users are not scope-restricted; personal access tokens only carry their granted scopes.
+func (u *AuthenticatedUser) HasScope(scope string) bool {
+	if u.TokenID == "" {
+		return true
+	}
+	for _, granted := range u.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAPITokens enables personal access token authentication on routes using AuthenticateWithScope.
+func (m *JWTMiddleware) WithAPITokens(store APITokenStore) *JWTMiddleware {
+	m.apiTokens = store
+	return m
+}
+
+// AuthenticateWithScope accepts either a Keycloak JWT or a personal access token granted scope.
+// Routes that only use Authenticate keep rejecting personal access tokens.
+func (m *JWTMiddleware) AuthenticateWithScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		jwtNext := m.Authenticate(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok || !strings.HasPrefix(token, APITokenPrefix) {
+				jwtNext.ServeHTTP(w, r)
+				return
+			}
+
+			if m.apiTokens == nil {
+				http.Error(w, `{"error": "invalid token"}`, http.StatusUnauthorized)
+				return
+			}
+
+			record, found, err := m.apiTokens.GetAPITokenByHash(r.Context(), HashAPIToken(token))
+			if err != nil {
+				Logger(r.Context()).Printf("api token lookup failed: %v", err)
+				http.Error(w, `{"error": "token lookup failed"}`, http.StatusInternalServerError)
+				return
+			}
+			if !found {
+				http.Error(w, `{"error": "invalid token"}`, http.StatusUnauthorized)
+				return
+			}
+
+			user := AuthenticatedUser{
+				ID:      record.UserID,
+				Scopes:  record.Scopes,
+				TokenID: record.ID,
+			}
+			if !user.HasScope(scope) {
+				http.Error(w, `{"error": "insufficient scope"}`, http.StatusForbidden)
+				return
+			}
+
+			if err := m.apiTokens.TouchAPIToken(r.Context(), record.ID, time.Now().Unix()); err != nil {
+				Logger(r.Context()).Printf("api token last_used update failed: %v", err)
+			}
+
+			ctx := context.WithValue(r.Context(), UserContextKey, &user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", false
+	}
+	return parts[1], true
+}
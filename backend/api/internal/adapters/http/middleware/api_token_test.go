@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gamedivers.de/api/internal/ports/repo"
+)
+
+type fakeAPITokenStore struct {
+	tokens  map[string]repo.APIToken
+	touched []string
+}
+
+func (s *fakeAPITokenStore) GetAPITokenByHash(_ context.Context, tokenHash string) (*repo.APIToken, bool, error) {
+	token, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, false, nil
+	}
+	return &token, true, nil
+}
+
+func (s *fakeAPITokenStore) TouchAPIToken(_ context.Context, tokenID string, _ int64) error {
+	s.touched = append(s.touched, tokenID)
+	return nil
+}
+
+func newPATTestMiddleware(plaintext string, scopes ...string) (*JWTMiddleware, *fakeAPITokenStore) {
+	store := &fakeAPITokenStore{tokens: map[string]repo.APIToken{
+		HashAPIToken(plaintext): {ID: "tok-1", UserID: "user-1", Label: "bot", Scopes: scopes},
+	}}
+	return NewJWTMiddleware("", "", "").WithAPITokens(store), store
+}
+
+func TestAuthenticateWithScopeAcceptsPAT(t *testing.T) {
+	m, store := newPATTestMiddleware("aio_pat_secret", ScopeLibraryRead)
+
+	var gotUser *AuthenticatedUser
+	handler := m.AuthenticateWithScope(ScopeLibraryRead)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = GetUserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/steam/library", nil)
+	req.Header.Set("Authorization", "Bearer aio_pat_secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if gotUser == nil || gotUser.ID != "user-1" || gotUser.TokenID != "tok-1" {
+		t.Fatalf("expected token user in context, got %+v", gotUser)
+	}
+	if len(store.touched) != 1 || store.touched[0] != "tok-1" {
+		t.Fatalf("expected last_used to be updated, got %v", store.touched)
+	}
+}
+
+func TestAuthenticateWithScopeRejectsMissingScope(t *testing.T) {
+	m, _ := newPATTestMiddleware("aio_pat_secret", ScopeLibraryRead)
+
+	handler := m.AuthenticateWithScope(ScopePricesRead)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called without the required scope")
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/itad/search", nil)
+	req.Header.Set("Authorization", "Bearer aio_pat_secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+}
+
+func TestAuthenticateWithScopeRejectsUnknownPAT(t *testing.T) {
+	m, _ := newPATTestMiddleware("aio_pat_secret", ScopeLibraryRead)
+
+	handler := m.AuthenticateWithScope(ScopeLibraryRead)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called for an unknown token")
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/steam/library", nil)
+	req.Header.Set("Authorization", "Bearer aio_pat_other")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", w.Code)
+	}
+}
+
+func TestAuthenticateRejectsPAT(t *testing.T) {
+	m, _ := newPATTestMiddleware("aio_pat_secret", ScopeLibraryRead)
+
+	handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("session-only routes must not accept personal access tokens")
+	}))
+
+	req := httptest.NewRequest("GET", "/v1/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer aio_pat_secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", w.Code)
+	}
+}
@@ -14,6 +14,14 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// jwksCacheTTL is how long fetched signing keys are trusted before the JWKS is refetched.
+	jwksCacheTTL = time.Hour
+	// jwksMinRefreshInterval bounds refetches triggered by unknown kids or a failing endpoint.
+	jwksMinRefreshInterval = 10 * time.Second
 )
 
 // ContextKey is a type for context keys
@@ -33,6 +41,10 @@ type AuthenticatedUser struct {
 	FirstName     string   `json:"given_name"`
 	LastName      string   `json:"family_name"`
 	Roles         []string `json:"roles"`
+
+	// Set only when authenticated with a personal access token
+	Scopes  []string `json:"-"`
+	TokenID string   `json:"-"`
 }
 
 // JWTMiddleware validates JWT tokens from Keycloak
@@ -43,6 +55,12 @@ type JWTMiddleware struct {
 	keys       map[string]*rsa.PublicKey
 	keysMutex  sync.RWMutex
 	httpClient *http.Client
+	apiTokens  APITokenStore
+
+	keysFetchedAt   time.Time
+	lastJWKSAttempt time.Time
+	jwksGroup       singleflight.Group
+	now             func() time.Time
 }
 
 // JWKS represents a JSON Web Key Set
@@ -70,17 +88,30 @@ func NewJWTMiddleware(jwksURL, issuer, clientID string) *JWTMiddleware {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		now: time.Now,
 	}
 }
 
-// fetchJWKS fetches the JSON Web Key Set from Keycloak
+// fetchJWKS fetches the JSON Web Key Set from Keycloak and replaces the cached keys,
+// so keys retired by a rotation stop validating.
 func (m *JWTMiddleware) fetchJWKS() error {
+	// Recorded once the attempt finishes so callers arriving mid-fetch still join it.
+	defer func() {
+		m.keysMutex.Lock()
+		m.lastJWKSAttempt = m.now()
+		m.keysMutex.Unlock()
+	}()
+
 	resp, err := m.httpClient.Get(m.jwksURL)
 	if err != nil {
 		return fmt.Errorf("fetch JWKS: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS status: %d", resp.StatusCode)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("read JWKS response: %w", err)
@@ -91,9 +122,7 @@ func (m *JWTMiddleware) fetchJWKS() error {
 		return fmt.Errorf("parse JWKS: %w", err)
 	}
 
-	m.keysMutex.Lock()
-	defer m.keysMutex.Unlock()
-
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
 	for _, jwk := range jwks.Keys {
 		if jwk.Kty != "RSA" {
 			continue
@@ -103,9 +132,37 @@ func (m *JWTMiddleware) fetchJWKS() error {
 		if err != nil {
 			continue
 		}
-		m.keys[jwk.Kid] = key
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS contains no usable RSA keys")
 	}
 
+	m.keysMutex.Lock()
+	m.keys = keys
+	m.keysFetchedAt = m.now()
+	m.keysMutex.Unlock()
+
+	return nil
+}
+
+// CheckJWKS verifies that the JWKS endpoint is reachable, for readiness probes
+func (m *JWTMiddleware) CheckJWKS(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.jwksURL, nil)
+	if err != nil {
+		return fmt.Errorf("build JWKS request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS status: %d", resp.StatusCode)
+	}
 	return nil
 }
 
@@ -135,30 +192,52 @@ func parseRSAPublicKey(jwk JWK) (*rsa.PublicKey, error) {
 	}, nil
 }
 
-// getKey returns the public key for the given key ID
+// getKey returns the public key for the given key ID. The JWKS is refetched once the
+// cache is older than jwksCacheTTL or when kid is unknown (key rotation); concurrent
+// callers share a single fetch.
 func (m *JWTMiddleware) getKey(kid string) (*rsa.PublicKey, error) {
-	m.keysMutex.RLock()
-	key, ok := m.keys[kid]
-	m.keysMutex.RUnlock()
-
-	if ok {
+	key, refresh := m.cachedKey(kid)
+	if !refresh {
+		if key == nil {
+			return nil, fmt.Errorf("key %s not found", kid)
+		}
 		return key, nil
 	}
 
-	// Key not found, try to refresh JWKS
-	if err := m.fetchJWKS(); err != nil {
+	_, err, _ := m.jwksGroup.Do("jwks", func() (interface{}, error) {
+		// A fetch that finished while this caller was waiting may already cover kid.
+		if _, refresh := m.cachedKey(kid); !refresh {
+			return nil, nil
+		}
+		return nil, m.fetchJWKS()
+	})
+
+	fetched, _ := m.cachedKey(kid)
+	if fetched != nil {
+		return fetched, nil
+	}
+	if err != nil {
+		if key != nil {
+			// Keycloak unreachable: keep accepting the previously fetched key.
+			return key, nil
+		}
 		return nil, err
 	}
+	return nil, fmt.Errorf("key %s not found", kid)
+}
 
+// cachedKey looks up kid and reports whether the JWKS should be refetched first.
+func (m *JWTMiddleware) cachedKey(kid string) (*rsa.PublicKey, bool) {
 	m.keysMutex.RLock()
-	key, ok = m.keys[kid]
-	m.keysMutex.RUnlock()
+	defer m.keysMutex.RUnlock()
 
-	if !ok {
-		return nil, fmt.Errorf("key %s not found", kid)
+	now := m.now()
+	key := m.keys[kid]
+	if now.Sub(m.lastJWKSAttempt) < jwksMinRefreshInterval {
+		return key, false
 	}
-
-	return key, nil
+	stale := now.Sub(m.keysFetchedAt) >= jwksCacheTTL
+	return key, key == nil || stale
 }
 
 // Authenticate is the middleware handler that validates JWT tokens
@@ -180,8 +259,8 @@ func (m *JWTMiddleware) Authenticate(next http.Handler) http.Handler {
 		tokenString := parts[1]
 
 		// Parse and validate the token
-		// Note: We don't validate audience because Keycloak access tokens typically have "account" as audience
-		// The azp (authorized party) claim contains the client_id
+		// Audience is checked after parsing with validateAudience: Keycloak access tokens typically
+		// have "account" as audience and carry the client_id in azp (authorized party).
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			// Validate signing algorithm
 			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
@@ -196,7 +275,8 @@ func (m *JWTMiddleware) Authenticate(next http.Handler) http.Handler {
 
 			return m.getKey(kid)
 		}, jwt.WithValidMethods([]string{"RS256"}),
-			jwt.WithIssuer(m.issuer))
+			jwt.WithIssuer(m.issuer),
+			jwt.WithExpirationRequired())
 
 		if err != nil {
 			http.Error(w, `{"error": "invalid token"}`, http.StatusUnauthorized)
@@ -237,6 +317,30 @@ func (m *JWTMiddleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// RoleAdmin is the Keycloak realm role required for admin endpoints
+const RoleAdmin = "admin"
+
+// RequireRole rejects requests whose authenticated user lacks the given realm role.
+// It must run after Authenticate.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				http.Error(w, `{"error": "missing authorization header"}`, http.StatusUnauthorized)
+				return
+			}
+			for _, granted := range user.Roles {
+				if granted == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, `{"error": "forbidden"}`, http.StatusForbidden)
+		})
+	}
+}
+
 // GetUserFromContext extracts the authenticated user from the request context
 func GetUserFromContext(ctx context.Context) (*AuthenticatedUser, bool) {
 	user, ok := ctx.Value(UserContextKey).(*AuthenticatedUser)
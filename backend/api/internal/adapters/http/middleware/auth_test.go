@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireRole(t *testing.T) {
+	handler := RequireRole(RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		roles []string
+		want  int
+	}{
+		{roles: []string{"user"}, want: http.StatusForbidden},
+		{roles: []string{"user", RoleAdmin}, want: http.StatusOK},
+	} {
+		req := httptest.NewRequest("GET", "/v1/admin/catalog/export.csv", nil)
+		req = req.WithContext(context.WithValue(req.Context(), UserContextKey, &AuthenticatedUser{ID: "u", Roles: tc.roles}))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Errorf("roles %v: expected status %d, got %d", tc.roles, tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the cap used when no positive limit is configured.
+const DefaultMaxBodyBytes = 1 << 20
+
+// BodyLimitOverride gives one trusted route its own cap, which may be higher than the
+// shared one. Path is the request path below the API mount, e.g. "/steam/wishlist/sync";
+// it matches the end of the URL path so the override applies under every mount.
+type BodyLimitOverride struct {
+	Path  string
+	Limit int64
+}
+
+// BodyLimit rejects request bodies larger than limit bytes with 413. A limit of zero or
+// less falls back to DefaultMaxBodyBytes. Overrides are matched on the URL path because a
+// group-level BodyLimit runs before chi has routed into nested subrouters.
+func BodyLimit(limit int64, overrides ...BodyLimitOverride) func(http.Handler) http.Handler {
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := routeBodyLimit(r, limit, overrides)
+			if r.ContentLength > limit {
+				writeBodyTooLarge(w, limit)
+				return
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				if r.ContentLength < 0 {
+					// Chunked bodies declare no length; buffer up to the cap so overflow still answers 413
+					// instead of surfacing as a decode error in the handler.
+					buf, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+					if err != nil {
+						writeJSONError(w, http.StatusBadRequest, "invalid_request", "Failed to read request body")
+						return
+					}
+					if int64(len(buf)) > limit {
+						writeBodyTooLarge(w, limit)
+						return
+					}
+					r.Body = io.NopCloser(bytes.NewReader(buf))
+				} else {
+					r.Body = http.MaxBytesReader(w, r.Body, limit)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func routeBodyLimit(r *http.Request, limit int64, overrides []BodyLimitOverride) int64 {
+	for _, o := range overrides {
+		if o.Limit > 0 && strings.HasSuffix(r.URL.Path, o.Path) {
+			return o.Limit
+		}
+	}
+	return limit
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, limit int64) {
+	w.Header().Set("Connection", "close")
+	writeJSONError(w, http.StatusRequestEntityTooLarge, "payload_too_large", "Request body must not exceed "+strconv.FormatInt(limit, 10)+" bytes")
+}
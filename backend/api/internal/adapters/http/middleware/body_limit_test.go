@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimit(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unexpected read error: %v", err)
+		}
+		_, _ = w.Write(body)
+	})
+	handler := BodyLimit(8)(echo)
+
+	tests := []struct {
+		name    string
+		body    string
+		chunked bool
+		want    int
+	}{
+		{"within limit", "12345678", false, http.StatusOK},
+		{"declared over limit", "123456789", false, http.StatusRequestEntityTooLarge},
+		{"chunked within limit", "1234", true, http.StatusOK},
+		{"chunked over limit", "123456789", true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			if tc.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, rec.Code)
+			}
+			if tc.want == http.StatusOK && rec.Body.String() != tc.body {
+				t.Fatalf("expected body %q to reach the handler, got %q", tc.body, rec.Body.String())
+			}
+			if tc.want == http.StatusRequestEntityTooLarge && !strings.Contains(rec.Body.String(), "payload_too_large") {
+				t.Fatalf("unexpected 413 body %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBodyLimitFallsBackToDefault(t *testing.T) {
+	handler := BodyLimit(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}")))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected a zero limit to fall back to the default, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", DefaultMaxBodyBytes+1))))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 above the default, got %d", rec.Code)
+	}
+}
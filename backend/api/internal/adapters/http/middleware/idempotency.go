@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// IdempotencyKeyHeader is the optional request header clients send to make retries safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	maxIdempotencyKeyLength = 255
+
+	// An in-flight key is released after this long even if its request never finished.
+	idempotencyInFlightTTL = 5 * time.Minute
+	// Caps keep one user from growing the store without bound.
+	maxIdempotencyEntries   = 10000
+	maxIdempotentBodyBytes  = 256 << 10
+	idempotencyCleanupEvery = time.Minute
+)
+
+type idempotentResponse struct {
+	route       string
+	done        bool
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// IdempotencyStore replays the stored response when an authenticated user repeats a
+// mutating request with the same Idempotency-Key. Requests without the header are untouched.
+type IdempotencyStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]*idempotentResponse
+	lastCleanup time.Time
+}
+
+func NewIdempotencyStore(ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*idempotentResponse),
+	}
+}
+
+// Middleware must run after authentication; keys are scoped per user.
+func (s *IdempotencyStore) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeJSONError(w, http.StatusBadRequest, "invalid_idempotency_key", "Idempotency-Key must be at most 255 characters.")
+			return
+		}
+
+		user, ok := GetUserFromContext(r.Context())
+		if !ok {
+			http.Error(w, `{"error": "missing authorization header"}`, http.StatusUnauthorized)
+			return
+		}
+		entryKey := user.ID + "\x00" + key
+		route := idempotencyRoute(r)
+
+		entry, stored, ok := s.reserve(entryKey, route)
+		if !ok {
+			w.Header().Set("Retry-After", "60")
+			writeJSONError(w, http.StatusServiceUnavailable, "idempotency_unavailable", "Too many requests are being tracked; retry later.")
+			return
+		}
+		if stored != nil {
+			switch {
+			case stored.route != route:
+				writeJSONError(w, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used for a different request.")
+			case !stored.done:
+				writeJSONError(w, http.StatusConflict, "idempotency_key_in_progress", "A request with this Idempotency-Key is still being processed.")
+			default:
+				if stored.contentType != "" {
+					w.Header().Set("Content-Type", stored.contentType)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(stored.status)
+				_, _ = w.Write(stored.body)
+			}
+			return
+		}
+
+		// A panic or an aborted request must not leave the key stuck in progress.
+		completed := false
+		defer func() {
+			if !completed {
+				s.release(entryKey, entry)
+			}
+		}()
+
+		rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if r.Context().Err() != nil {
+			return
+		}
+		s.complete(entryKey, entry, rec)
+		completed = true
+	})
+}
+
+// idempotencyRoute identifies the endpoint a key was used on. The /api/v1 compatibility
+// mount serves the same routes as /v1, so a key is valid across both prefixes.
+func idempotencyRoute(r *http.Request) string {
+	route := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			route = pattern
+		}
+	}
+	return strings.TrimPrefix(route, "/api")
+}
+
+// reserve returns a copy of the stored entry for key, or records a new in-flight entry
+// and returns it. ok is false when the store is full.
+func (s *IdempotencyStore) reserve(key, route string) (entry *idempotentResponse, stored *idempotentResponse, ok bool) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastCleanup.IsZero() || now.Sub(s.lastCleanup) > idempotencyCleanupEvery || len(s.entries) >= maxIdempotencyEntries {
+		for k, e := range s.entries {
+			if !now.Before(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastCleanup = now
+	}
+
+	if e, found := s.entries[key]; found && now.Before(e.expiresAt) {
+		copied := *e
+		return nil, &copied, true
+	}
+	if len(s.entries) >= maxIdempotencyEntries {
+		return nil, nil, false
+	}
+	entry = &idempotentResponse{route: route, expiresAt: now.Add(idempotencyInFlightTTL)}
+	s.entries[key] = entry
+	return entry, nil, true
+}
+
+// complete stores the response. Server errors and oversized bodies are forgotten so the
+// client can retry.
+func (s *IdempotencyStore) complete(key string, entry *idempotentResponse, rec *idempotencyRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries[key] != entry {
+		// The in-flight entry expired and the key was reused; leave the newer entry alone.
+		return
+	}
+	if rec.status >= http.StatusInternalServerError || rec.truncated {
+		delete(s.entries, key)
+		return
+	}
+	entry.done = true
+	entry.status = rec.status
+	entry.contentType = rec.Header().Get("Content-Type")
+	entry.body = rec.body.Bytes()
+	entry.expiresAt = s.now().Add(s.ttl)
+}
+
+// release drops an in-flight entry whose request did not complete.
+func (s *IdempotencyStore) release(key string, entry *idempotentResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries[key] == entry {
+		delete(s.entries, key)
+	}
+}
+
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	truncated   bool
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if !r.truncated {
+		if r.body.Len()+len(p) > maxIdempotentBodyBytes {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func idempotentRequest(user, key, path string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return req.WithContext(context.WithValue(req.Context(), UserContextKey, &AuthenticatedUser{ID: user}))
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyStore(24 * time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"synced":` + strconv.Itoa(calls) + `}`))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("user-1", "key-1", "/v1/steam/wishlist/sync"))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest("user-1", "key-1", "/v1/steam/wishlist/sync"))
+
+	if calls != 1 {
+		t.Fatalf("expected one logical effect, handler ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replay of %d %q, got %d %q", first.Code, first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected Idempotent-Replayed header on replay")
+	}
+
+	// Keys are scoped per user, and requests without a key are never deduplicated.
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-2", "key-1", "/v1/steam/wishlist/sync"))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "", "/v1/steam/wishlist/sync"))
+	if calls != 3 {
+		t.Fatalf("expected 3 handler runs, got %d", calls)
+	}
+}
+
+func TestIdempotencyRejectsKeyReuseOnOtherPath(t *testing.T) {
+	handler := NewIdempotencyStore(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("user-1", "key-1", "/v1/epic/sync"))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+}
+
+func TestIdempotencyForgetsServerErrorsAndExpiredKeys(t *testing.T) {
+	store := NewIdempotencyStore(time.Hour)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+
+	status := http.StatusBadGateway
+	calls := 0
+	handler := store.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	status = http.StatusOK
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	if calls != 2 {
+		t.Fatalf("expected retry after 502 then replay, handler ran %d times", calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	if calls != 3 {
+		t.Fatalf("expected expired key to run again, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyWhenHandlerPanics(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyStore(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	func() {
+		defer func() { _ = recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	}()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	if w.Code != http.StatusOK || calls != 2 {
+		t.Fatalf("expected the retry to run after a panic, got %d after %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyWhenClientAborts(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyStore(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync").WithContext(
+		context.WithValue(ctx, UserContextKey, &AuthenticatedUser{ID: "user-1"}),
+	))
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	if calls != 2 {
+		t.Fatalf("expected the aborted request not to be replayed, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotencyInFlightKeyExpires(t *testing.T) {
+	store := NewIdempotencyStore(time.Hour)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+
+	if _, _, ok := store.reserve("user-1\x00key-1", "/v1/steam/sync"); !ok {
+		t.Fatal("expected the key to be reserved")
+	}
+	if _, stored, _ := store.reserve("user-1\x00key-1", "/v1/steam/sync"); stored == nil || stored.done {
+		t.Fatal("expected the key to be in progress")
+	}
+
+	now = now.Add(idempotencyInFlightTTL + time.Second)
+	if entry, stored, ok := store.reserve("user-1\x00key-1", "/v1/steam/sync"); !ok || stored != nil || entry == nil {
+		t.Fatal("expected a stuck in-flight key to be reserved again")
+	}
+}
+
+func TestIdempotencyKeyWorksAcrossAPIPrefix(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyStore(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("user-1", "key-1", "/api/v1/steam/sync"))
+	if calls != 1 || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected a replay on the /api mount, got %d with %d calls", w.Code, calls)
+	}
+}
+
+func TestIdempotencySkipsOversizedResponses(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyStore(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write(make([]byte, maxIdempotentBodyBytes+1))
+	}))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	if first.Body.Len() != maxIdempotentBodyBytes+1 {
+		t.Fatalf("expected the full body to reach the client, got %d bytes", first.Body.Len())
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("user-1", "key-1", "/v1/steam/sync"))
+	if calls != 2 {
+		t.Fatalf("expected an oversized response not to be stored, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotencyRejectsWhenStoreIsFull(t *testing.T) {
+	store := NewIdempotencyStore(time.Hour)
+	for i := range maxIdempotencyEntries {
+		store.reserve("user-1\x00"+strconv.Itoa(i), "/v1/steam/sync")
+	}
+	handler := store.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not run when the store is full")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, idempotentRequest("user-2", "key-1", "/v1/steam/sync"))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", w.Code)
+	}
+}
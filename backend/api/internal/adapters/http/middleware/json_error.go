@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSONError writes the {error, message} body the API handlers use.
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":   code,
+		"message": message,
+	})
+}
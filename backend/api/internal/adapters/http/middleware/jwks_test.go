@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://keycloak.test/realms/aio"
+	testClientID = "aio-frontend"
+)
+
+type testJWKSServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches atomic.Int32
+}
+
+func newTestJWKSServer(t *testing.T) *testJWKSServer {
+	t.Helper()
+	s := &testJWKSServer{keys: map[string]*rsa.PrivateKey{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var jwks JWKS
+		for kid, key := range s.keys {
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kid: kid,
+				Kty: "RSA",
+				Alg: "RS256",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// rotate replaces the published key set with a single new key.
+func (s *testJWKSServer) rotate(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	s.mu.Lock()
+	s.keys = map[string]*rsa.PrivateKey{kid: key}
+	s.mu.Unlock()
+	return key
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "user-1",
+		"iss": testIssuer,
+		"azp": testClientID,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func authStatus(m *JWTMiddleware, token string) int {
+	handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/steam/library", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestJWKSRotationRefetchesOnce(t *testing.T) {
+	server := newTestJWKSServer(t)
+	oldKey := server.rotate(t, "old")
+
+	now := time.Now()
+	m := NewJWTMiddleware(server.URL, testIssuer, testClientID)
+	m.now = func() time.Time { return now }
+
+	if code := authStatus(m, signTestToken(t, oldKey, "old", validClaims())); code != http.StatusOK {
+		t.Fatalf("old key: expected 200, got %d", code)
+	}
+	if code := authStatus(m, signTestToken(t, oldKey, "old", validClaims())); code != http.StatusOK {
+		t.Fatalf("cached key: expected 200, got %d", code)
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Fatalf("expected 1 JWKS fetch, got %d", got)
+	}
+
+	newKey := server.rotate(t, "new")
+	now = now.Add(jwksMinRefreshInterval)
+	token := signTestToken(t, newKey, "new", validClaims())
+
+	var wg sync.WaitGroup
+	codes := make([]int, 20)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = authStatus(m, token)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 after rotation, got %d", i, code)
+		}
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Fatalf("expected one refetch for the rotation burst, got %d fetches", got)
+	}
+
+	// The retired key is gone from the refreshed set.
+	if code := authStatus(m, signTestToken(t, oldKey, "old", validClaims())); code != http.StatusUnauthorized {
+		t.Fatalf("retired key: expected 401, got %d", code)
+	}
+}
+
+func TestJWKSUnknownKidRejected(t *testing.T) {
+	server := newTestJWKSServer(t)
+	server.rotate(t, "current")
+	m := NewJWTMiddleware(server.URL, testIssuer, testClientID)
+
+	rogue, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	if code := authStatus(m, signTestToken(t, rogue, "unknown", validClaims())); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", code)
+	}
+	// Repeated unknown kids must not hammer Keycloak.
+	authStatus(m, signTestToken(t, rogue, "unknown-2", validClaims()))
+	if got := server.fetches.Load(); got != 1 {
+		t.Fatalf("expected 1 JWKS fetch, got %d", got)
+	}
+}
+
+func TestJWKSCacheExpires(t *testing.T) {
+	server := newTestJWKSServer(t)
+	key := server.rotate(t, "k1")
+
+	now := time.Now()
+	m := NewJWTMiddleware(server.URL, testIssuer, testClientID)
+	m.now = func() time.Time { return now }
+
+	token := signTestToken(t, key, "k1", validClaims())
+	authStatus(m, token)
+	now = now.Add(jwksCacheTTL)
+	if code := authStatus(m, token); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Fatalf("expected refetch after TTL, got %d fetches", got)
+	}
+}
+
+func TestAuthenticateValidatesClaims(t *testing.T) {
+	server := newTestJWKSServer(t)
+	key := server.rotate(t, "k1")
+	m := NewJWTMiddleware(server.URL, testIssuer, testClientID)
+
+	for name, mutate := range map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.test/realms/aio" },
+		"wrong audience": func(c jwt.MapClaims) { c["azp"] = "other-client" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"missing exp":    func(c jwt.MapClaims) { delete(c, "exp") },
+	} {
+		claims := validClaims()
+		mutate(claims)
+		if code := authStatus(m, signTestToken(t, key, "k1", claims)); code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401, got %d", name, code)
+		}
+	}
+}
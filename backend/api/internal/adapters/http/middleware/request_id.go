@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+// RequestIDHeader carries the correlation ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDContextKey is the context key for the request correlation ID
+const RequestIDContextKey ContextKey = "request_id"
+
+// Incoming IDs are only reused when they are short and log-safe.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID assigns every request a correlation ID, reusing a valid incoming X-Request-ID,
+// stores it in the context and echoes it in the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), RequestIDContextKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetRequestID returns the request's correlation ID, or "" outside a request.
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDContextKey).(string)
+	return id
+}
+
+// Logger returns a logger that prefixes lines with the request's correlation ID.
+func Logger(ctx context.Context) *log.Logger {
+	id := GetRequestID(ctx)
+	if id == "" {
+		return log.Default()
+	}
+	return log.New(log.Writer(), "req="+id+" ", log.Flags()|log.Lmsgprefix)
+}
+
+func newRequestID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
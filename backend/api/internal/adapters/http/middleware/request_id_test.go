@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDReusesValidHeader(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = GetRequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/steam/sync", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if seen != "abc-123" || w.Header().Get(RequestIDHeader) != "abc-123" {
+		t.Fatalf("expected request id abc-123, got context %q header %q", seen, w.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestRequestIDReplacesInvalidHeader(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = GetRequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/steam/sync", nil)
+	req.Header.Set(RequestIDHeader, "bad id with spaces")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if seen == "" || seen == "bad id with spaces" {
+		t.Fatalf("expected a generated request id, got %q", seen)
+	}
+	if w.Header().Get(RequestIDHeader) != seen {
+		t.Fatalf("expected response header %q, got %q", seen, w.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestLoggerPrefixesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	original := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(original)
+
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Logger(r.Context()).Printf("sync failed")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/steam/sync", nil)
+	req.Header.Set(RequestIDHeader, "trace-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(buf.String(), "req=trace-1 sync failed") {
+		t.Fatalf("expected prefixed log line, got %q", buf.String())
+	}
+}
@@ -14,54 +14,33 @@ import (
 	authmw "gamedivers.de/api/internal/adapters/http/middleware"
 )
 
-func Router(frontendOrigin string, itadh *handlers.ITADHandler, gameHandler *handlers.GameHandler, steamHandler *handlers.SteamHandler, epicHandler *handlers.EpicHandler, authh *handlers.AuthHandler, jwtMw *authmw.JWTMiddleware) *chi.Mux {
+func Router(corsOrigins []string, maxBodyBytes int64, itadh *handlers.ITADHandler, gameHandler *handlers.GameHandler, steamHandler *handlers.SteamHandler, epicHandler *handlers.EpicHandler, authh *handlers.AuthHandler, tokenh *handlers.APITokenHandler, adminh *handlers.AdminHandler, priceh *handlers.PriceHandler, healthh *handlers.HealthHandler, jwtMw *authmw.JWTMiddleware) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.RealIP)
-	r.Use(middleware.RequestID)
+	r.Use(authmw.RequestID)
 	r.Use(requestLogMiddleware)
 	r.Use(middleware.Recoverer)
 
-	allowedOrigins := map[string]struct{}{}
-	if normalized := normalizeOrigin(frontendOrigin); normalized != "" {
-		allowedOrigins[normalized] = struct{}{}
-	}
 	sensitiveAuthLimiter := authmw.NewIPRateLimiter(rate.Every(12*time.Second), 5, 15*time.Minute)
 	tokenAuthLimiter := authmw.NewIPRateLimiter(rate.Every(time.Second), 20, 15*time.Minute)
+	idempotency := authmw.NewIdempotencyStore(24 * time.Hour)
 
-	// CORS middleware for frontend
-	r.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			rawOrigin := r.Header.Get("Origin")
-			if rawOrigin == "" {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			origin := normalizeOrigin(rawOrigin)
-			if _, ok := allowedOrigins[origin]; ok || isLocalOrigin(origin) {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Vary", "Origin")
-				w.Header().Set("Access-Control-Allow-Credentials", "true")
-			}
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type")
-
-			if r.Method == http.MethodOptions {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	})
+	r.Use(corsMiddleware(corsOrigins))
 
-	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+	// Liveness only confirms the process is up; readiness also checks the database and Keycloak.
+	r.Get("/health", healthh.Live)
+	r.Get("/healthz", healthh.Ready)
+	r.Get("/readyz", healthh.Ready)
 
 	register := func(router chi.Router) {
-		registerV1Routes(router, itadh, gameHandler, steamHandler, epicHandler, authh, jwtMw, sensitiveAuthLimiter, tokenAuthLimiter)
+		// Every v1 route shares the body cap; trusted routes that need more are listed as overrides.
+		router.Group(func(router chi.Router) {
+			router.Use(authmw.BodyLimit(maxBodyBytes,
+				// Large wishlists are posted in one request.
+				authmw.BodyLimitOverride{Path: "/steam/wishlist/sync", Limit: 8 << 20},
+			))
+			registerV1Routes(router, itadh, gameHandler, steamHandler, epicHandler, authh, tokenh, adminh, priceh, jwtMw, sensitiveAuthLimiter, tokenAuthLimiter, idempotency)
+		})
 	}
 	r.Route("/v1", register)
 	// Compatibility route for ingress setups that forward /api without stripping the prefix.
@@ -77,15 +56,18 @@ func registerV1Routes(
 	steamHandler *handlers.SteamHandler,
 	epicHandler *handlers.EpicHandler,
 	authh *handlers.AuthHandler,
+	tokenh *handlers.APITokenHandler,
+	adminh *handlers.AdminHandler,
+	priceh *handlers.PriceHandler,
 	jwtMw *authmw.JWTMiddleware,
 	sensitiveAuthLimiter *authmw.IPRateLimiter,
 	tokenAuthLimiter *authmw.IPRateLimiter,
+	idempotency *authmw.IdempotencyStore,
 ) {
 	// Game endpoints: keep a single mount path to avoid chi route collisions
 	r.Route("/games", func(r chi.Router) {
-		r.Get("/installed", gameHandler.GetInstalledGames)
-
-		// Authenticated launch endpoints
+		// Authenticated local scan and launch endpoints (the response exposes local install paths)
+		r.With(jwtMw.Authenticate).Get("/installed", gameHandler.GetInstalledGames)
 		r.With(jwtMw.Authenticate).Post("/steam/{appid}/start", gameHandler.StartSteamGame)
 		r.With(jwtMw.Authenticate).Post("/epic/{appname}/start", gameHandler.StartEpicGame)
 		r.With(jwtMw.Authenticate).Post("/gog/{gamename}/start", gameHandler.StartGOGGame)
@@ -111,6 +93,13 @@ func registerV1Routes(
 			r.Get("/me", authh.GetMe)
 			r.Put("/password", authh.ChangePassword)
 			r.Put("/profile", authh.UpdateProfile)
+			r.Put("/region", authh.UpdateRegion)
+			r.Delete("/account", authh.DeleteAccount)
+
+			// Personal access tokens (session only, never manageable with a token)
+			r.Get("/tokens", tokenh.List)
+			r.Post("/tokens", tokenh.Create)
+			r.Delete("/tokens/{tokenId}", tokenh.Revoke)
 		})
 	})
 
@@ -119,26 +108,44 @@ func registerV1Routes(
 		r.Get("/login", steamHandler.LoginRedirect)
 		r.Get("/callback", steamHandler.Callback)
 
-		// Authenticated Steam endpoints
-		r.With(jwtMw.Authenticate).Get("/library", steamHandler.GetLibrary)
-		r.With(jwtMw.Authenticate).Get("/wishlist", steamHandler.GetWishlist)
-		r.With(jwtMw.Authenticate).Post("/wishlist/sync", steamHandler.SyncWishlistToWatchlist)
-		r.With(jwtMw.Authenticate).Post("/sync", steamHandler.SyncLibrary)
+		// Authenticated Steam endpoints (read endpoints also accept personal access tokens)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeLibraryRead)).Get("/library", steamHandler.GetLibrary)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeLibraryRead)).Get("/library/{appid}/achievements", steamHandler.GetAchievements)
+		r.With(jwtMw.Authenticate).Post("/library/{appid}/achievements/sync", steamHandler.SyncAchievements)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeLibraryRead)).Get("/recent", steamHandler.GetRecentlyPlayed)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeLibraryRead)).Get("/profile", steamHandler.GetProfile)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeLibraryRead)).Get("/friends-owning", steamHandler.GetFriendsOwning)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeWishlistRead)).Get("/wishlist", steamHandler.GetWishlist)
+		r.With(jwtMw.Authenticate, idempotency.Middleware).Post("/wishlist/sync", steamHandler.SyncWishlistToWatchlist)
+		r.With(jwtMw.Authenticate, idempotency.Middleware).Post("/sync", steamHandler.SyncLibrary)
 	})
 
 	// Epic endpoints
 	r.Route("/epic", func(r chi.Router) {
 		r.Get("/login", epicHandler.LoginRedirect)
 		r.Get("/callback", epicHandler.Callback)
+		r.With(tokenAuthLimiter.Middleware).Post("/exchange", epicHandler.Exchange)
 
 		// Authenticated Epic endpoints
-		r.With(jwtMw.Authenticate).Get("/library", epicHandler.GetLibrary)
-		r.With(jwtMw.Authenticate).Post("/sync", epicHandler.SyncLibrary)
+		r.With(jwtMw.AuthenticateWithScope(authmw.ScopeLibraryRead)).Get("/library", epicHandler.GetLibrary)
+		r.With(jwtMw.Authenticate, idempotency.Middleware).Post("/sync", epicHandler.SyncLibrary)
+	})
+
+	// Admin endpoints (admin realm role required)
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(jwtMw.Authenticate)
+		r.Use(authmw.RequireRole(authmw.RoleAdmin))
+
+		r.Get("/catalog/export.csv", adminh.ExportCatalogCSV)
+		r.Get("/migrations", adminh.GetMigrations)
+		r.Post("/migrations/run", adminh.RunMigrations)
+		r.Get("/price-refresh", adminh.GetPriceRefreshStats)
+		r.Get("/steam/appdetails-breaker", adminh.GetSteamAppDetailsBreaker)
 	})
 
 	// Protected API endpoints (authentication required)
 	r.Group(func(r chi.Router) {
-		r.Use(jwtMw.Authenticate)
+		r.Use(jwtMw.AuthenticateWithScope(authmw.ScopePricesRead))
 
 		// IsThereAnyDeal endpoints - provides prices from all stores including Steam
 		r.Route("/itad", func(r chi.Router) {
@@ -151,6 +158,9 @@ func registerV1Routes(
 			// Get price overview for multiple games
 			r.Get("/overview", itadh.GetOverview)
 
+			// Get current deals for multiple games in as few upstream calls as possible
+			r.Get("/prices", itadh.GetPrices)
+
 			// Game-specific endpoints
 			r.Route("/games/{gameId}", func(r chi.Router) {
 				// Get combined game details (info + prices + history)
@@ -166,9 +176,54 @@ func registerV1Routes(
 				r.Get("/historylow", itadh.GetHistoricalLow)
 			})
 		})
+
+		// Stored Steam prices per store region, and their recorded history
+		r.Get("/prices/steam/{appid}", priceh.GetSteamPrice)
+		r.Get("/prices/games/{gameId}/history", priceh.GetPriceHistory)
 	})
 }
 
+// corsMiddleware echoes the request origin back only when it is on the allow-list.
+// Credentials are allowed, so a wildcard origin is never sent.
+func corsMiddleware(origins []string) func(http.Handler) http.Handler {
+	allowedOrigins := map[string]struct{}{}
+	for _, origin := range origins {
+		if normalized := normalizeOrigin(origin); normalized != "" {
+			allowedOrigins[normalized] = struct{}{}
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawOrigin := r.Header.Get("Origin")
+			if rawOrigin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			origin := normalizeOrigin(rawOrigin)
+			if _, ok := allowedOrigins[origin]; !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, Idempotency-Key, If-None-Match, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, X-Request-ID, X-Total-Count")
+
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func normalizeOrigin(origin string) string {
 	u, err := url.Parse(strings.TrimSpace(origin))
 	if err != nil || u.Scheme == "" || u.Host == "" {
@@ -176,16 +231,3 @@ func normalizeOrigin(origin string) string {
 	}
 	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
 }
-
-func isLocalOrigin(origin string) bool {
-	u, err := url.Parse(strings.TrimSpace(origin))
-	if err != nil {
-		return false
-	}
-	switch strings.ToLower(u.Hostname()) {
-	case "localhost", "127.0.0.1", "::1":
-		return true
-	default:
-		return false
-	}
-}
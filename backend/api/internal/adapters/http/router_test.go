@@ -0,0 +1,92 @@
+package httpapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gamedivers.de/api/internal/adapters/http/handlers"
+	authmw "gamedivers.de/api/internal/adapters/http/middleware"
+)
+
+func serveCORS(origins []string, method, origin string) *httptest.ResponseRecorder {
+	handler := corsMiddleware(origins)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(method, "/v1/auth/me", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSAllowsListedOrigin(t *testing.T) {
+	w := serveCORS([]string{"https://gamedivers.de", "http://localhost:1420"}, http.MethodGet, "HTTP://LOCALHOST:1420")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:1420" {
+		t.Fatalf("expected the request origin to be echoed, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatal("expected credentials to be allowed")
+	}
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected the request to reach the handler, got %d", w.Code)
+	}
+}
+
+func TestCORSRejectsUnlistedOrigin(t *testing.T) {
+	for _, origin := range []string{"https://evil.example", "http://localhost:5173", "null"} {
+		w := serveCORS([]string{"https://gamedivers.de", "http://localhost:1420"}, http.MethodOptions, origin)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("origin %q: expected no Access-Control-Allow-Origin, got %q", origin, got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+			t.Errorf("origin %q: expected no Access-Control-Allow-Credentials, got %q", origin, got)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	w := serveCORS([]string{"https://gamedivers.de"}, http.MethodOptions, "https://gamedivers.de")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected preflight status 200, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Fatal("expected allowed methods on preflight")
+	}
+}
+
+func TestRouterRaisesBodyLimitForWishlistSync(t *testing.T) {
+	router := Router(
+		[]string{"https://gamedivers.de"}, 1<<20,
+		&handlers.ITADHandler{}, &handlers.GameHandler{}, handlers.NewSteamHandler("", "", "https://gamedivers.de", nil, nil),
+		handlers.NewEpicHandler("", "", "", "https://gamedivers.de", nil), &handlers.AuthHandler{}, &handlers.APITokenHandler{},
+		&handlers.AdminHandler{}, &handlers.PriceHandler{}, &handlers.HealthHandler{},
+		authmw.NewJWTMiddleware("http://127.0.0.1:0/jwks", "issuer", "client"),
+	)
+
+	tests := []struct {
+		path string
+		size int
+		want int
+	}{
+		// Past the body cap the unauthenticated request is rejected by auth, not with 413.
+		{"/v1/steam/wishlist/sync", 2 << 20, http.StatusUnauthorized},
+		{"/api/v1/steam/wishlist/sync", 2 << 20, http.StatusUnauthorized},
+		{"/v1/steam/wishlist/sync", 9 << 20, http.StatusRequestEntityTooLarge},
+		{"/v1/steam/sync", 2 << 20, http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, bytes.NewReader(make([]byte, tc.size))))
+		if w.Code != tc.want {
+			t.Errorf("POST %s with %d bytes: expected %d, got %d", tc.path, tc.size, tc.want, w.Code)
+		}
+	}
+}
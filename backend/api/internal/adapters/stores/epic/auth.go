@@ -3,6 +3,7 @@ package epic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,16 +11,43 @@ import (
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"gamedivers.de/api/internal/adapters/stores/storehttp"
+	"gamedivers.de/api/internal/ports/store"
 )
 
+const epicTokenURL = "https://api.epicgames.dev/epic/oauth/v1/token"
+
 type Client struct {
 	clientID     string
 	clientSecret string
 	redirectURI  string
+	tokenURL     string
 	httpClient   *http.Client
 	limiter      *rate.Limiter
 }
 
+// ScopeConsentError is returned by ExchangeCode when Epic requires the user to
+// grant (additional) scopes before a token is issued. The user has to be sent
+// to ContinuationURL to finish the consent flow.
+type ScopeConsentError struct {
+	Continuation    string
+	ContinuationURL string
+}
+
+func (e *ScopeConsentError) Error() string {
+	return "epic scope consent required"
+}
+
+// epicErrorResponse is the error body returned by the Epic OAuth endpoints
+type epicErrorResponse struct {
+	ErrorCode        string `json:"errorCode"`
+	ErrorMessage     string `json:"errorMessage"`
+	CorrectiveAction string `json:"correctiveAction"`
+	Continuation     string `json:"continuation"`
+	ContinuationURL  string `json:"continuationUrl"`
+}
+
 type OAuthTokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	ExpiresIn    int    `json:"expires_in"`
@@ -45,13 +73,28 @@ func NewClient(clientID, clientSecret, redirectURI string) *Client {
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		redirectURI:  redirectURI,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		limiter: rate.NewLimiter(rate.Every(time.Second), 5),
+		tokenURL:     epicTokenURL,
+		httpClient:   storehttp.NewClient("epic", 10*time.Second),
+		limiter:      rate.NewLimiter(rate.Every(time.Second), 5),
 	}
 }
 
+// SetRateLimit overrides the default request rate (requests per second) and burst shared
+// by all calls made through this client. Non-positive values keep the current setting.
+func (c *Client) SetRateLimit(perSecond float64, burst int) {
+	if perSecond > 0 {
+		c.limiter.SetLimit(rate.Limit(perSecond))
+	}
+	if burst > 0 {
+		c.limiter.SetBurst(burst)
+	}
+}
+
+// Configured reports whether OAuth client credentials were provided.
+func (c *Client) Configured() bool {
+	return c.clientID != "" && c.clientSecret != ""
+}
+
 func (c *Client) GetLoginURL(state string) string {
 	params := url.Values{}
 	params.Set("client_id", c.clientID)
@@ -64,6 +107,9 @@ func (c *Client) GetLoginURL(state string) string {
 }
 
 func (c *Client) ExchangeCode(ctx context.Context, code string) (*OAuthTokenResponse, error) {
+	if !c.Configured() {
+		return nil, fmt.Errorf("%w: epic client credentials missing", store.ErrProviderNotConfigured)
+	}
 	if err := c.limiter.Wait(ctx); err != nil {
 		return nil, err
 	}
@@ -73,7 +119,7 @@ func (c *Client) ExchangeCode(ctx context.Context, code string) (*OAuthTokenResp
 	data.Set("code", code)
 	data.Set("redirect_uri", c.redirectURI)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.epicgames.dev/epic/oauth/v1/token", nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +136,14 @@ func (c *Client) ExchangeCode(ctx context.Context, code string) (*OAuthTokenResp
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+
+		var epicErr epicErrorResponse
+		if json.Unmarshal(body, &epicErr) == nil && epicErr.CorrectiveAction == "SCOPE_CONSENT" {
+			return nil, &ScopeConsentError{
+				Continuation:    epicErr.Continuation,
+				ContinuationURL: epicErr.ContinuationURL,
+			}
+		}
 		return nil, fmt.Errorf("token exchange failed: %s - %s", resp.Status, string(body))
 	}
 
@@ -162,3 +216,12 @@ func (c *Client) GetAccountInfo(ctx context.Context, accessToken string) (map[st
 
 	return accountInfo, nil
 }
+
+// IsScopeConsentRequired reports whether err asks the user to grant scopes, returning the details.
+func IsScopeConsentRequired(err error) (*ScopeConsentError, bool) {
+	var consentErr *ScopeConsentError
+	if errors.As(err, &consentErr) {
+		return consentErr, true
+	}
+	return nil, false
+}
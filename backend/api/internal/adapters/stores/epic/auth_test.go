@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestGetLoginURL(t *testing.T) {
@@ -52,14 +54,79 @@ func TestExchangeCode(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient("test_id", "test_secret", "http://localhost/callback")
-	
-	// Note: This would need the actual Epic API endpoint to be mocked
-	// For now, this is a placeholder test
+	client.tokenURL = server.URL
+
 	ctx := context.Background()
-	_, err := client.ExchangeCode(ctx, "test_code")
-	
-	// We expect an error since we're not hitting the real Epic API
-	if err == nil {
-		t.Log("ExchangeCode test - would need Epic API mock")
+	tokenResp, err := client.ExchangeCode(ctx, "test_code")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokenResp.AccessToken != "test_token" || tokenResp.AccountID != "test_account_123" {
+		t.Fatalf("unexpected token response %+v", tokenResp)
+	}
+}
+
+func TestExchangeCodeScopeConsentRequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		// Recorded from the Epic token endpoint when the app requests a scope the user hasn't granted yet.
+		fmt.Fprintln(w, `{
+			"errorCode": "errors.com.epicgames.oauth.corrective_action_required",
+			"errorMessage": "Corrective action is required to continue.",
+			"messageVars": [],
+			"numericErrorCode": 18206,
+			"originatingService": "com.epicgames.account.public",
+			"intent": "prod",
+			"correctiveAction": "SCOPE_CONSENT",
+			"continuation": "c0a6d8e2f1b34c5d9e7f",
+			"continuationUrl": "https://www.epicgames.com/id/login/scope-consent?continuation=c0a6d8e2f1b34c5d9e7f",
+			"error": "corrective_action_required"
+		}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.tokenURL = server.URL
+
+	_, err := client.ExchangeCode(context.Background(), "test_code")
+	consentErr, ok := IsScopeConsentRequired(err)
+	if !ok {
+		t.Fatalf("expected ScopeConsentError, got %v", err)
+	}
+	if consentErr.Continuation != "c0a6d8e2f1b34c5d9e7f" {
+		t.Errorf("unexpected continuation %q", consentErr.Continuation)
+	}
+	if consentErr.ContinuationURL != "https://www.epicgames.com/id/login/scope-consent?continuation=c0a6d8e2f1b34c5d9e7f" {
+		t.Errorf("unexpected continuation URL %q", consentErr.ContinuationURL)
+	}
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"access_token": "test_token"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.tokenURL = server.URL
+	client.SetRateLimit(20, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.ExchangeCode(context.Background(), "test_code"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Burst 1 at 20 req/s: the second and third request each wait ~50ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected requests to be spaced, 3 requests took %v", elapsed)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 requests, got %d", calls.Load())
 	}
 }
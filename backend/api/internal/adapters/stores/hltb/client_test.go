@@ -0,0 +1,129 @@
+package hltb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const searchFixture = `{"data":[
+	{"game_id":4064,"game_name":"Half-Life 2: Episode One","comp_main":16200,"comp_plus":19800,"comp_100":27000},
+	{"game_id":4063,"game_name":"Half-Life 2","comp_main":46800,"comp_plus":57600,"comp_100":0}]}`
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := New()
+	c.baseURL = server.URL
+	c.limiter = nil
+	return c
+}
+
+func TestEstimatePrefersExactMatch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/search" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			SearchTerms []string `json:"searchTerms"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.SearchTerms) != 2 {
+			t.Errorf("unexpected search terms %v (%v)", body.SearchTerms, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(searchFixture))
+	})
+
+	estimate, err := c.Estimate(context.Background(), "Half-Life 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate == nil || estimate.GameID != 4063 {
+		t.Fatalf("expected Half-Life 2, got %+v", estimate)
+	}
+	if estimate.MainStoryHours == nil || *estimate.MainStoryHours != 13 {
+		t.Fatalf("expected 13h main story, got %v", estimate.MainStoryHours)
+	}
+	if estimate.MainExtraHours == nil || *estimate.MainExtraHours != 16 {
+		t.Fatalf("expected 16h main + extra, got %v", estimate.MainExtraHours)
+	}
+	if estimate.CompletionistHours != nil {
+		t.Fatalf("expected no completionist estimate, got %v", *estimate.CompletionistHours)
+	}
+}
+
+func TestEstimateCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	})
+	now := time.Unix(1_700_000_000, 0)
+	c.now = func() time.Time { return now }
+
+	for range 2 {
+		if estimate, err := c.Estimate(context.Background(), "Unknown Game"); err != nil || estimate != nil {
+			t.Fatalf("expected no estimate, got %+v (%v)", estimate, err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected the miss to be cached, got %d searches", calls.Load())
+	}
+
+	now = now.Add(notFoundTTL + time.Second)
+	if _, err := c.Estimate(context.Background(), "unknown game!"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected a new search after the miss expired, got %d searches", calls.Load())
+	}
+}
+
+func TestEstimateUpstreamError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	if _, err := c.Estimate(context.Background(), "Portal"); err == nil {
+		t.Fatal("expected an error for a rejected search")
+	}
+}
+
+func TestEstimateCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(searchFixture))
+	})
+	c.maxEntries = 2
+	ctx := context.Background()
+
+	for _, title := range []string{"Half-Life 2", "Portal", "Half-Life 2", "Portal 2"} {
+		if _, err := c.Estimate(ctx, title); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls.Load() != 3 || len(c.cache) != 2 {
+		t.Fatalf("expected 3 searches and 2 cached titles, got %d and %d", calls.Load(), len(c.cache))
+	}
+
+	// "Portal" was least recently used and was evicted; "Half-Life 2" is still cached.
+	if _, err := c.Estimate(ctx, "Half-Life 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected the recently used title to stay cached, got %d searches", calls.Load())
+	}
+	if _, err := c.Estimate(ctx, "Portal"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected the evicted title to be searched again, got %d searches", calls.Load())
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"gamedivers.de/api/internal/adapters/stores/storehttp"
 )
 
 // Client handles communication with the IsThereAnyDeal API
@@ -24,7 +26,7 @@ type Client struct {
 // New creates a new ITAD client with API key authentication
 func New(apiKey string) *Client {
 	return &Client{
-		http:    &http.Client{Timeout: 15 * time.Second},
+		http:    storehttp.NewClient("itad", 15*time.Second),
 		limiter: rate.NewLimiter(1, 5), // 1 request per second, burst of 5
 		apiKey:  apiKey,
 		baseURL: "https://api.isthereanydeal.com",
@@ -122,8 +124,16 @@ type PricesResponse struct {
 }
 
 type GamePrices struct {
-	ID    string `json:"id"`
-	Deals []Deal `json:"deals"`
+	ID         string      `json:"id"`
+	HistoryLow *HistoryLow `json:"historyLow,omitempty"`
+	Deals      []Deal      `json:"deals"`
+}
+
+// HistoryLow holds the all-time, one-year and three-month lowest prices across shops
+type HistoryLow struct {
+	All *PriceInfo `json:"all"`
+	Y1  *PriceInfo `json:"y1"`
+	M3  *PriceInfo `json:"m3"`
 }
 
 type Deal struct {
@@ -140,6 +150,8 @@ type Deal struct {
 	Timestamp  string     `json:"timestamp"`
 	Expiry     *string    `json:"expiry"`
 	URL        string     `json:"url"`
+	// StoreID is our canonical store id for Shop, set by the API; empty for shops we don't track.
+	StoreID string `json:"storeId,omitempty"`
 }
 
 type Shop struct {
@@ -240,6 +252,52 @@ func (c *Client) GetGamePrices(ctx context.Context, gameID string, country strin
 	return c.doRequest(ctx, http.MethodPost, endpoint, params, body)
 }
 
+// pricesBatchSize is the most game IDs the prices endpoint accepts per request.
+const pricesBatchSize = 200
+
+// Prices gets current deals for many games, batching IDs into as few requests as the API allows.
+// The result is keyed by ITAD game ID; games ITAD doesn't know are absent.
+func (c *Client) Prices(ctx context.Context, ids []string, country string) (map[string]GamePrices, error) {
+	out := make(map[string]GamePrices, len(ids))
+	for start := 0; start < len(ids); start += pricesBatchSize {
+		batch := ids[start:min(start+pricesBatchSize, len(ids))]
+
+		data, err := c.pricesBatch(ctx, batch, country)
+		if err != nil {
+			return nil, err
+		}
+
+		var games []GamePrices
+		if err := json.Unmarshal(data, &games); err != nil {
+			return nil, fmt.Errorf("decode prices: %w", err)
+		}
+		for _, game := range games {
+			out[game.ID] = game
+		}
+	}
+	return out, nil
+}
+
+func (c *Client) pricesBatch(ctx context.Context, ids []string, country string) (json.RawMessage, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/games/prices/v3", c.baseURL)
+	params := url.Values{}
+	if country != "" {
+		params.Set("country", country)
+	}
+	params.Set("vouchers", "true")
+
+	body, err := json.Marshal(ids)
+	if err != nil {
+		return nil, fmt.Errorf("marshal body: %w", err)
+	}
+
+	return c.doRequest(ctx, http.MethodPost, endpoint, params, body)
+}
+
 // GetOverview gets price overview for multiple games
 func (c *Client) GetOverview(ctx context.Context, gameIDs []string, country string) (json.RawMessage, error) {
 	if err := c.limiter.Wait(ctx); err != nil {
@@ -315,7 +373,6 @@ func (c *Client) doRequest(ctx context.Context, method string, endpoint string,
 	if method == http.MethodPost {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	req.Header.Set("User-Agent", "gamedivers/1.0")
 
 	resp, err := c.http.Do(req)
 	if err != nil {
@@ -0,0 +1,92 @@
+package itad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const pricesFixture = `[
+  {"id":"018d937f-21e1-728e-86d7-9acb3c59f2bb","historyLow":{"all":{"amount":1.99,"amountInt":199,"currency":"EUR"}},
+   "deals":[
+    {"shop":{"id":61,"name":"Steam"},"price":{"amount":4.99,"amountInt":499,"currency":"EUR"},"regular":{"amount":19.99,"amountInt":1999,"currency":"EUR"},"cut":75,"url":"https://itad.link/steam"},
+    {"shop":{"id":35,"name":"GOG"},"price":{"amount":9.99,"amountInt":999,"currency":"EUR"},"regular":{"amount":19.99,"amountInt":1999,"currency":"EUR"},"cut":50,"url":"https://itad.link/gog"}]},
+  {"id":"018d937f-0b1a-72a3-a0d6-8d1f4d1b6a55","deals":[
+    {"shop":{"id":16,"name":"Epic Game Store"},"price":{"amount":0,"amountInt":0,"currency":"EUR"},"regular":{"amount":29.99,"amountInt":2999,"currency":"EUR"},"cut":100,"url":"https://itad.link/epic"}]}
+]`
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := New("test-key")
+	c.baseURL = server.URL
+	return c
+}
+
+func TestPricesExtractsDealsPerGame(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/games/prices/v3" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("country") != "DE" {
+			t.Errorf("expected country DE, got %q", r.URL.Query().Get("country"))
+		}
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil || len(ids) != 3 {
+			t.Errorf("expected 3 ids in one request, got %v (%v)", ids, err)
+		}
+		_, _ = w.Write([]byte(pricesFixture))
+	})
+
+	prices, err := c.Prices(context.Background(), []string{
+		"018d937f-21e1-728e-86d7-9acb3c59f2bb",
+		"018d937f-0b1a-72a3-a0d6-8d1f4d1b6a55",
+		"018d937f-ffff-7000-8000-000000000000",
+	}, "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("expected 2 games, got %d", len(prices))
+	}
+
+	first := prices["018d937f-21e1-728e-86d7-9acb3c59f2bb"]
+	if len(first.Deals) != 2 || first.Deals[0].Shop.Name != "Steam" || first.Deals[0].Price.AmountInt != 499 || first.Deals[1].Cut != 50 {
+		t.Fatalf("unexpected deals %+v", first.Deals)
+	}
+	if first.HistoryLow == nil || first.HistoryLow.All == nil || first.HistoryLow.All.AmountInt != 199 {
+		t.Fatalf("unexpected history low %+v", first.HistoryLow)
+	}
+
+	second := prices["018d937f-0b1a-72a3-a0d6-8d1f4d1b6a55"]
+	if len(second.Deals) != 1 || second.Deals[0].Cut != 100 || second.Deals[0].Shop.ID != 16 {
+		t.Fatalf("unexpected deals %+v", second.Deals)
+	}
+}
+
+func TestPricesBatchesLargeRequests(t *testing.T) {
+	var sizes []int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var ids []string
+		_ = json.NewDecoder(r.Body).Decode(&ids)
+		sizes = append(sizes, len(ids))
+		_, _ = w.Write([]byte(`[]`))
+	})
+	c.limiter.SetLimit(1000)
+
+	ids := make([]string, pricesBatchSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("game-%d", i)
+	}
+	if _, err := c.Prices(context.Background(), ids, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sizes) != 2 || sizes[0] != pricesBatchSize || sizes[1] != 1 {
+		t.Fatalf("expected batches of %d and 1, got %v", pricesBatchSize, sizes)
+	}
+}
@@ -0,0 +1,118 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrSteamProfilePrivate is returned when Steam refuses to expose a profile's game data.
+var ErrSteamProfilePrivate = errors.New("steam_profile_private")
+
+// Achievement represents a single achievement entry for a player
+type Achievement struct {
+	APIName    string `json:"apiName"`
+	Achieved   bool   `json:"achieved"`
+	UnlockTime int64  `json:"unlockTime,omitempty"`
+}
+
+// AchievementSummary contains a player's achievement progress for one app
+type AchievementSummary struct {
+	AppID        int           `json:"appId"`
+	GameName     string        `json:"gameName,omitempty"`
+	Unlocked     int           `json:"unlocked"`
+	Total        int           `json:"total"`
+	Achievements []Achievement `json:"achievements"`
+}
+
+// GetAchievements retrieves the player's achievements for a single app.
+// Games without an achievement schema yield an empty summary, not an error.
+func (c *Client) GetAchievements(ctx context.Context, steamID string, appID int) (*AchievementSummary, error) {
+	if err := c.requireAPIKey(); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/ISteamUserStats/GetPlayerAchievements/v1/", c.apiURL)
+
+	params := url.Values{}
+	params.Set("key", c.apiKey)
+	params.Set("steamid", steamID)
+	params.Set("appid", fmt.Sprintf("%d", appID))
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build achievements request")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch achievements")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read achievements response: %w", err)
+	}
+
+	var result struct {
+		PlayerStats struct {
+			GameName     string `json:"gameName"`
+			Success      bool   `json:"success"`
+			Error        string `json:"error"`
+			Achievements []struct {
+				APIName    string `json:"apiname"`
+				Achieved   int    `json:"achieved"`
+				UnlockTime int64  `json:"unlocktime"`
+			} `json:"achievements"`
+		} `json:"playerstats"`
+	}
+	// Steam reports "no stats" and "private profile" as error bodies on 4xx responses.
+	_ = json.Unmarshal(body, &result)
+
+	summary := &AchievementSummary{
+		AppID:        appID,
+		GameName:     result.PlayerStats.GameName,
+		Achievements: []Achievement{},
+	}
+
+	statsErr := strings.ToLower(result.PlayerStats.Error)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden,
+		strings.Contains(statsErr, "not public"):
+		return nil, ErrSteamProfilePrivate
+	case strings.Contains(statsErr, "no stats"):
+		return summary, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("steam api error: %d", resp.StatusCode)
+	case !result.PlayerStats.Success:
+		return nil, fmt.Errorf("steam achievements unavailable")
+	}
+
+	for _, a := range result.PlayerStats.Achievements {
+		achieved := a.Achieved == 1
+		if achieved {
+			summary.Unlocked++
+		}
+		summary.Achievements = append(summary.Achievements, Achievement{
+			APIName:    a.APIName,
+			Achieved:   achieved,
+			UnlockTime: a.UnlockTime,
+		})
+	}
+	summary.Total = len(summary.Achievements)
+
+	return summary, nil
+}
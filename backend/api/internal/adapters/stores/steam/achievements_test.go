@@ -0,0 +1,72 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient("test-key", "")
+	c.apiURL = server.URL
+	c.limiter = nil
+	c.apiLimiter = nil
+	return c
+}
+
+func TestGetAchievementsCountsUnlocked(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("appid") != "620" {
+			t.Errorf("expected appid 620, got %q", r.URL.Query().Get("appid"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"playerstats":{"steamID":"1","gameName":"Portal 2","success":true,"achievements":[
+			{"apiname":"ACH_A","achieved":1,"unlocktime":1700000000},
+			{"apiname":"ACH_B","achieved":0,"unlocktime":0},
+			{"apiname":"ACH_C","achieved":1,"unlocktime":1700000100}]}}`))
+	})
+
+	summary, err := c.GetAchievements(context.Background(), "76561198000000000", 620)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Unlocked != 2 || summary.Total != 3 {
+		t.Fatalf("expected 2/3 achievements, got %d/%d", summary.Unlocked, summary.Total)
+	}
+	if summary.GameName != "Portal 2" {
+		t.Fatalf("expected game name Portal 2, got %q", summary.GameName)
+	}
+}
+
+func TestGetAchievementsNoSchemaReturnsEmpty(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"playerstats":{"error":"Requested app has no stats","success":false}}`))
+	})
+
+	summary, err := c.GetAchievements(context.Background(), "76561198000000000", 440)
+	if err != nil {
+		t.Fatalf("expected no error for app without stats, got %v", err)
+	}
+	if summary.Total != 0 || len(summary.Achievements) != 0 {
+		t.Fatalf("expected empty summary, got %+v", summary)
+	}
+}
+
+func TestGetAchievementsPrivateProfile(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"playerstats":{"error":"Profile is not public","success":false}}`))
+	})
+
+	_, err := c.GetAchievements(context.Background(), "76561198000000000", 620)
+	if !errors.Is(err, ErrSteamProfilePrivate) {
+		t.Fatalf("expected ErrSteamProfilePrivate, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,33 +16,72 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"golang.org/x/time/rate"
 
+	"gamedivers.de/api/internal/adapters/stores/storehttp"
 	"gamedivers.de/api/internal/ports/store"
 )
 
 const (
 	steamOpenIDURL = "https://steamcommunity.com/openid/login"
 	steamAPIURL    = "https://api.steampowered.com"
+	steamStoreURL  = "https://store.steampowered.com"
+
+	// appListTTL is how long the full Steam app list is served before it is refreshed.
+	appListTTL = 24 * time.Hour
+	// appListRetryDelay keeps a failed refresh from being retried on every lookup.
+	appListRetryDelay = 5 * time.Minute
+	// priceCacheTTL briefly reuses appdetails prices per (appid, region).
+	priceCacheTTL = 5 * time.Minute
+	// priceCacheMaxEntries caps the price cache; new prices aren't cached while it is full of live entries.
+	priceCacheMaxEntries = 5000
+	// appDetailsWorkers bounds concurrent appdetails chunk requests.
+	appDetailsWorkers = 3
+	// Web API (api.steampowered.com) calls are paced separately from the store's appdetails,
+	// which Steam throttles far more aggressively.
+	webAPIRate  = 4
+	webAPIBurst = 10
 )
 
 // Client handles Steam authentication, API calls, and pricing
 type Client struct {
 	apiKey      string
 	callbackURL string
+	apiURL      string
+	storeURL    string
+	openIDURL   string
 	httpClient  *http.Client
-	limiter     *rate.Limiter
-	appListOnce sync.Once
-	appListMu   sync.RWMutex
-	appList     map[int]string
-	appListErr  error
+	// limiter paces store.steampowered.com (appdetails and prices); apiLimiter paces the Web API.
+	limiter    *rate.Limiter
+	apiLimiter *rate.Limiter
+	appDetails *appDetailsBreaker
+
+	priceMu         sync.Mutex
+	priceCache      map[string]cachedPrice
+	priceCacheSwept time.Time
+
+	friendsMu     sync.Mutex
+	friendsOwning map[string]cachedFriendsOwning
+
+	appListMu        sync.RWMutex
+	appList          map[int]string
+	appListFetchedAt time.Time
+	appListRetryAt   time.Time
+	appListGroup     singleflight.Group
 }
 
 // New creates a Steam client for pricing (no auth needed)
 func New() *Client {
 	return &Client{
-		httpClient: &http.Client{Timeout: 12 * time.Second},
+		apiURL:     steamAPIURL,
+		storeURL:   steamStoreURL,
+		openIDURL:  steamOpenIDURL,
+		httpClient: storehttp.NewClient("steam", 12*time.Second),
 		limiter:    rate.NewLimiter(0.6, 5),
+		apiLimiter: rate.NewLimiter(webAPIRate, webAPIBurst),
+		appDetails: newAppDetailsBreaker(),
+		priceCache: map[string]cachedPrice{},
 	}
 }
 
@@ -50,15 +90,35 @@ func NewClient(apiKey, callbackURL string) *Client {
 	return &Client{
 		apiKey:      apiKey,
 		callbackURL: callbackURL,
-		httpClient: &http.Client{
-			Timeout: 40 * time.Second,
-		},
-		limiter: rate.NewLimiter(0.6, 5),
+		apiURL:      steamAPIURL,
+		storeURL:    steamStoreURL,
+		openIDURL:   steamOpenIDURL,
+		httpClient:  storehttp.NewClient("steam", 40*time.Second),
+		limiter:     rate.NewLimiter(0.6, 5),
+		apiLimiter:  rate.NewLimiter(webAPIRate, webAPIBurst),
+		appDetails:  newAppDetailsBreaker(),
+		priceCache:  map[string]cachedPrice{},
 	}
 }
 
 func (c *Client) StoreID() string { return "steam" }
 
+// waitWebAPI blocks until the Web API limiter admits another call.
+func (c *Client) waitWebAPI(ctx context.Context) error {
+	if c.apiLimiter == nil {
+		return nil
+	}
+	return c.apiLimiter.Wait(ctx)
+}
+
+// requireAPIKey guards the Web API calls that cannot work without a key.
+func (c *Client) requireAPIKey() error {
+	if c.apiKey == "" {
+		return fmt.Errorf("%w: steam api key missing", store.ErrProviderNotConfigured)
+	}
+	return nil
+}
+
 // --- Pricing API (existing) ---
 
 type appDetailsResp map[string]struct {
@@ -74,29 +134,59 @@ type appDetailsResp map[string]struct {
 	} `json:"data"`
 }
 
-func (c *Client) FetchDEPrice(ctx context.Context, externalGameID string) (*store.Price, string, error) {
+// ErrInvalidRegion is returned for store regions that are not two-letter country codes.
+var ErrInvalidRegion = errors.New("steam_invalid_region")
+
+type cachedPrice struct {
+	price     *store.Price
+	name      string
+	fetchedAt time.Time
+}
+
+// FetchPrice returns the app's price in the store region cc. Results are cached
+// per (appid, region) for priceCacheTTL so bursts of lookups hit Steam once;
+// store.WithoutCache skips the cached entry.
+func (c *Client) FetchPrice(ctx context.Context, externalGameID, cc string) (*store.Price, string, error) {
+	cc = strings.ToLower(strings.TrimSpace(cc))
+	if !steamRegionPattern.MatchString(cc) {
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidRegion, cc)
+	}
+
+	key := externalGameID + "|" + cc
+	if !store.CacheBypassed(ctx) {
+		c.priceMu.Lock()
+		cached, ok := c.priceCache[key]
+		c.priceMu.Unlock()
+		if ok && time.Since(cached.fetchedAt) < priceCacheTTL {
+			return cached.price, cached.name, nil
+		}
+	}
+
+	if err := c.appDetails.allow(); err != nil {
+		return nil, "", err
+	}
 	if c.limiter != nil {
 		if err := c.limiter.Wait(ctx); err != nil {
 			return nil, "", err
 		}
 	}
 
-	url := fmt.Sprintf(
-		"https://store.steampowered.com/api/appdetails?appids=%s&cc=de&filters=basic,price_overview",
-		externalGameID,
-	)
+	params := url.Values{}
+	params.Set("appids", externalGameID)
+	params.Set("cc", cc)
+	params.Set("filters", "basic,price_overview")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.storeURL+"/api/appdetails?"+params.Encode(), nil)
 	if err != nil {
 		return nil, "", err
 	}
-	req.Header.Set("User-Agent", "gamedivers/1.0 (contact: you@example.com)")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, "", err
 	}
 	defer resp.Body.Close()
+	c.appDetails.record(resp.StatusCode)
 
 	if resp.StatusCode == 429 || resp.StatusCode >= 500 {
 		return nil, "", fmt.Errorf("steam temporary error: %d", resp.StatusCode)
@@ -110,23 +200,41 @@ func (c *Client) FetchDEPrice(ctx context.Context, externalGameID string) (*stor
 		return nil, "", err
 	}
 
-	entry, ok := parsed[externalGameID]
-	if !ok || !entry.Success {
-		return nil, "", nil
+	var price *store.Price
+	var name string
+	if entry, ok := parsed[externalGameID]; ok && entry.Success {
+		name = entry.Data.Name
+		if po := entry.Data.PriceOverview; po != nil {
+			price = &store.Price{
+				Currency:        po.Currency,
+				InitialCents:    po.Initial,
+				FinalCents:      po.Final,
+				DiscountPercent: po.DiscountPercent,
+			}
+		}
 	}
 
-	name := entry.Data.Name
-	if entry.Data.PriceOverview == nil {
-		return nil, name, nil
-	}
+	c.cachePrice(key, cachedPrice{price: price, name: name, fetchedAt: time.Now()})
+	return price, name, nil
+}
 
-	po := entry.Data.PriceOverview
-	return &store.Price{
-		Currency:        po.Currency,
-		InitialCents:    po.Initial,
-		FinalCents:      po.Final,
-		DiscountPercent: po.DiscountPercent,
-	}, name, nil
+// cachePrice stores a fetched price, sweeping expired entries once per TTL or when the cache is full.
+func (c *Client) cachePrice(key string, entry cachedPrice) {
+	c.priceMu.Lock()
+	defer c.priceMu.Unlock()
+
+	if entry.fetchedAt.Sub(c.priceCacheSwept) > priceCacheTTL || len(c.priceCache) >= priceCacheMaxEntries {
+		for k, existing := range c.priceCache {
+			if entry.fetchedAt.Sub(existing.fetchedAt) >= priceCacheTTL {
+				delete(c.priceCache, k)
+			}
+		}
+		c.priceCacheSwept = entry.fetchedAt
+	}
+	if _, exists := c.priceCache[key]; !exists && len(c.priceCache) >= priceCacheMaxEntries {
+		return
+	}
+	c.priceCache[key] = entry
 }
 
 // --- Authentication & Library API (new) ---
@@ -141,7 +249,7 @@ func (c *Client) GetLoginURL(returnURL string) string {
 	params.Set("openid.identity", "http://specs.openid.net/auth/2.0/identifier_select")
 	params.Set("openid.claimed_id", "http://specs.openid.net/auth/2.0/identifier_select")
 
-	return steamOpenIDURL + "?" + params.Encode()
+	return c.openIDURL + "?" + params.Encode()
 }
 
 func openIDRealmFromReturnURL(returnURL string) string {
@@ -152,12 +260,31 @@ func openIDRealmFromReturnURL(returnURL string) string {
 	return u.Scheme + "://" + u.Host
 }
 
-// VerifyCallback verifies the Steam OpenID callback and extracts Steam ID
+// VerifyCallback verifies the Steam OpenID callback and extracts Steam ID.
+// The assertion is only trusted after Steam confirms its signature via check_authentication.
 func (c *Client) VerifyCallback(values url.Values) (string, error) {
+	if values.Get("openid.op_endpoint") != steamOpenIDURL {
+		return "", fmt.Errorf("unexpected openid endpoint")
+	}
+
+	// Extract Steam ID from claimed_id
+	claimedID := values.Get("openid.claimed_id")
+	if claimedID != values.Get("openid.identity") {
+		return "", fmt.Errorf("claimed id does not match identity")
+	}
+	matches := steamClaimedIDPattern.FindStringSubmatch(claimedID)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("failed to extract steam id")
+	}
+
 	// Change mode to check_authentication
-	values.Set("openid.mode", "check_authentication")
+	verify := url.Values{}
+	for key, value := range values {
+		verify[key] = value
+	}
+	verify.Set("openid.mode", "check_authentication")
 
-	resp, err := c.httpClient.PostForm(steamOpenIDURL, values)
+	resp, err := c.httpClient.PostForm(c.openIDURL, verify)
 	if err != nil {
 		return "", fmt.Errorf("failed to verify: %w", err)
 	}
@@ -169,21 +296,19 @@ func (c *Client) VerifyCallback(values url.Values) (string, error) {
 	}
 
 	// Check if authentication is valid
-	if !regexp.MustCompile(`is_valid\s*:\s*true`).Match(body) {
+	if resp.StatusCode != http.StatusOK || !steamIsValidPattern.Match(body) {
 		return "", fmt.Errorf("invalid authentication")
 	}
 
-	// Extract Steam ID from claimed_id
-	claimedID := values.Get("openid.claimed_id")
-	re := regexp.MustCompile(`^https://steamcommunity\.com/openid/id/([0-9]{17})/?$`)
-	matches := re.FindStringSubmatch(claimedID)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("failed to extract steam id")
-	}
-
 	return matches[1], nil
 }
 
+var (
+	steamClaimedIDPattern = regexp.MustCompile(`^https://steamcommunity\.com/openid/id/([0-9]{17})/?$`)
+	steamIsValidPattern   = regexp.MustCompile(`(?m)^is_valid\s*:\s*true\s*$`)
+	steamRegionPattern    = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
 // Game represents a Steam game
 type Game struct {
 	AppID           int    `json:"appid"`
@@ -202,6 +327,9 @@ type PlayerSummary struct {
 	Avatar       string `json:"avatar"`
 	AvatarMedium string `json:"avatarmedium"`
 	AvatarFull   string `json:"avatarfull"`
+	// CommunityVisibilityState is 3 for public profiles; private ones omit TimeCreated.
+	CommunityVisibilityState int   `json:"communityvisibilitystate"`
+	TimeCreated              int64 `json:"timecreated"`
 }
 
 // WishlistEntry represents a Steam wishlist entry returned by the store endpoint.
@@ -217,6 +345,8 @@ type WishlistItem struct {
 	Name    string `json:"name"`
 	Capsule string `json:"capsule,omitempty"`
 	Added   int64  `json:"added"`
+	// Priority is the user's wishlist rank (1 = top); 0 means unranked.
+	Priority int `json:"priority"`
 }
 
 type AppMetadata struct {
@@ -227,22 +357,25 @@ type AppMetadata struct {
 var ErrSteamWishlistPrivate = errors.New("steam_wishlist_private")
 
 // GetWishlist retrieves the user's Steam wishlist via the public store endpoint.
-func (c *Client) GetWishlist(steamID string) ([]WishlistItem, error) {
-	if c.apiKey == "" {
-		return nil, fmt.Errorf("steam api key missing")
+func (c *Client) GetWishlist(ctx context.Context, steamID string) ([]WishlistItem, error) {
+	if err := c.requireAPIKey(); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return nil, err
 	}
 
-	endpoint := "https://api.steampowered.com/IWishlistService/GetWishlist/v1"
+	endpoint := fmt.Sprintf("%s/IWishlistService/GetWishlist/v1", c.apiURL)
 	params := url.Values{}
 	params.Set("key", c.apiKey)
 	params.Set("steamid", steamID)
 
-	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build wishlist request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "gamedivers/1.0")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -281,7 +414,7 @@ func (c *Client) GetWishlist(steamID string) ([]WishlistItem, error) {
 		appIDs = append(appIDs, entry.AppID)
 	}
 
-	metadataByID := c.getAppMetadataBatch(appIDs, 50)
+	metadataByID := c.getAppMetadataBatch(ctx, appIDs, 50)
 	items := make([]WishlistItem, 0, len(raw.Response.Items))
 	for _, entry := range raw.Response.Items {
 		meta := metadataByID[entry.AppID]
@@ -294,10 +427,11 @@ func (c *Client) GetWishlist(steamID string) ([]WishlistItem, error) {
 			capsule = defaultCapsuleURL(entry.AppID)
 		}
 		items = append(items, WishlistItem{
-			AppID:   entry.AppID,
-			Name:    name,
-			Capsule: capsule,
-			Added:   entry.DateAdded,
+			AppID:    entry.AppID,
+			Name:     name,
+			Capsule:  capsule,
+			Added:    entry.DateAdded,
+			Priority: entry.Priority,
 		})
 	}
 
@@ -305,7 +439,7 @@ func (c *Client) GetWishlist(steamID string) ([]WishlistItem, error) {
 	return items, nil
 }
 
-func (c *Client) getAppMetadataBatch(appIDs []int, chunkSize int) map[int]AppMetadata {
+func (c *Client) getAppMetadataBatch(ctx context.Context, appIDs []int, chunkSize int) map[int]AppMetadata {
 	out := make(map[int]AppMetadata, len(appIDs))
 	if len(appIDs) == 0 {
 		return out
@@ -314,28 +448,12 @@ func (c *Client) getAppMetadataBatch(appIDs []int, chunkSize int) map[int]AppMet
 		chunkSize = 25
 	}
 
+	var chunks [][]int
 	for i := 0; i < len(appIDs); i += chunkSize {
-		end := i + chunkSize
-		if end > len(appIDs) {
-			end = len(appIDs)
-		}
-		chunk := appIDs[i:end]
-		metadata, err := c.fetchAppDetailsChunk(chunk)
-		if err != nil {
-			continue
-		}
-		for id, meta := range metadata {
-			current := out[id]
-			if current.Name == "" {
-				current.Name = strings.TrimSpace(meta.Name)
-			}
-			if current.Capsule == "" {
-				current.Capsule = strings.TrimSpace(meta.Capsule)
-			}
-			if current.Name != "" || current.Capsule != "" {
-				out[id] = current
-			}
-		}
+		chunks = append(chunks, appIDs[i:min(i+chunkSize, len(appIDs))])
+	}
+	for id, meta := range c.fetchAppDetailsChunks(ctx, chunks) {
+		out[id] = meta
 	}
 
 	missing := make([]int, 0)
@@ -347,7 +465,11 @@ func (c *Client) getAppMetadataBatch(appIDs []int, chunkSize int) map[int]AppMet
 
 	if len(missing) > 0 {
 		for _, id := range missing {
-			meta, err := c.fetchSingleAppMetadata(id)
+			if c.appDetails.allow() != nil {
+				// Paused: leave the rest to the cached app list.
+				break
+			}
+			meta, err := c.fetchSingleAppMetadata(ctx, id)
 			if err != nil {
 				continue
 			}
@@ -372,7 +494,7 @@ func (c *Client) getAppMetadataBatch(appIDs []int, chunkSize int) map[int]AppMet
 	}
 
 	if len(missing) > 0 {
-		if fromList, err := c.getAppNamesFromList(missing); err == nil {
+		if fromList, err := c.getAppNamesFromList(ctx, missing); err == nil {
 			for id, name := range fromList {
 				if strings.TrimSpace(name) == "" {
 					continue
@@ -386,19 +508,74 @@ func (c *Client) getAppMetadataBatch(appIDs []int, chunkSize int) map[int]AppMet
 		}
 	}
 
+	var unresolved []int
 	for _, id := range appIDs {
 		meta := out[id]
+		if strings.TrimSpace(meta.Name) == "" {
+			unresolved = append(unresolved, id)
+		}
 		if strings.TrimSpace(meta.Capsule) == "" {
 			meta.Capsule = defaultCapsuleURL(id)
 		}
 		out[id] = meta
 	}
+	if len(unresolved) > 0 {
+		log.Printf("steam: could not resolve names for %d apps: %v", len(unresolved), unresolved)
+	}
+
+	return out
+}
+
+// fetchAppDetailsChunks fetches appdetails for chunks with bounded concurrency; the client's
+// limiter still paces the requests. A failed chunk is retried once before its ids are left
+// to the per-app and app-list fallbacks.
+func (c *Client) fetchAppDetailsChunks(ctx context.Context, chunks [][]int) map[int]AppMetadata {
+	out := make(map[int]AppMetadata)
+	var mu sync.Mutex
+
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	for range min(appDetailsWorkers, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				metadata, err := c.fetchAppDetailsChunk(ctx, chunk)
+				if err != nil && ctx.Err() == nil && !errors.Is(err, ErrAppDetailsThrottled) {
+					metadata, err = c.fetchAppDetailsChunk(ctx, chunk)
+				}
+				if err != nil {
+					log.Printf("steam: appdetails failed for apps %v: %v", chunk, err)
+					continue
+				}
+
+				mu.Lock()
+				for id, meta := range metadata {
+					meta.Name = strings.TrimSpace(meta.Name)
+					meta.Capsule = strings.TrimSpace(meta.Capsule)
+					if meta.Name != "" || meta.Capsule != "" {
+						out[id] = meta
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, chunk := range chunks {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- chunk
+	}
+	close(jobs)
+	wg.Wait()
 
 	return out
 }
 
-func (c *Client) fetchSingleAppMetadata(appID int) (AppMetadata, error) {
-	metadata, err := c.fetchAppDetailsChunk([]int{appID})
+func (c *Client) fetchSingleAppMetadata(ctx context.Context, appID int) (AppMetadata, error) {
+	metadata, err := c.fetchAppDetailsChunk(ctx, []int{appID})
 	if err != nil {
 		return AppMetadata{}, err
 	}
@@ -406,7 +583,7 @@ func (c *Client) fetchSingleAppMetadata(appID int) (AppMetadata, error) {
 	meta := metadata[appID]
 	if strings.TrimSpace(meta.Name) == "" {
 		// Retry once without "basic" filters. Some app pages return a name only in the unfiltered payload.
-		retry, retryErr := c.fetchAppDetailsChunkWithFilters([]int{appID}, "")
+		retry, retryErr := c.fetchAppDetailsChunkWithFilters(ctx, []int{appID}, "")
 		if retryErr == nil {
 			retryMeta := retry[appID]
 			if strings.TrimSpace(meta.Name) == "" {
@@ -427,13 +604,16 @@ func (c *Client) fetchSingleAppMetadata(appID int) (AppMetadata, error) {
 	return meta, nil
 }
 
-func (c *Client) fetchAppDetailsChunk(appIDs []int) (map[int]AppMetadata, error) {
-	return c.fetchAppDetailsChunkWithFilters(appIDs, "basic")
+func (c *Client) fetchAppDetailsChunk(ctx context.Context, appIDs []int) (map[int]AppMetadata, error) {
+	return c.fetchAppDetailsChunkWithFilters(ctx, appIDs, "basic")
 }
 
-func (c *Client) fetchAppDetailsChunkWithFilters(appIDs []int, filters string) (map[int]AppMetadata, error) {
+func (c *Client) fetchAppDetailsChunkWithFilters(ctx context.Context, appIDs []int, filters string) (map[int]AppMetadata, error) {
+	if err := c.appDetails.allow(); err != nil {
+		return nil, err
+	}
 	if c.limiter != nil {
-		if err := c.limiter.Wait(context.Background()); err != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
 			return nil, err
 		}
 	}
@@ -450,12 +630,10 @@ func (c *Client) fetchAppDetailsChunkWithFilters(appIDs []int, filters string) (
 	if strings.TrimSpace(filters) != "" {
 		params.Set("filters", filters)
 	}
-	endpoint := fmt.Sprintf("https://store.steampowered.com/api/appdetails?%s", params.Encode())
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.storeURL+"/api/appdetails?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", "gamedivers/1.0")
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -463,6 +641,7 @@ func (c *Client) fetchAppDetailsChunkWithFilters(appIDs []int, filters string) (
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.appDetails.record(resp.StatusCode)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("appdetails error: %d", resp.StatusCode)
@@ -508,35 +687,71 @@ func defaultCapsuleURL(appID int) string {
 	return fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%d/capsule_184x69.jpg", appID)
 }
 
-func (c *Client) getAppNamesFromList(appIDs []int) (map[int]string, error) {
-	c.appListOnce.Do(func() {
-		c.appList, c.appListErr = c.fetchFullAppList()
-	})
-	if c.appListErr != nil {
-		return nil, c.appListErr
+func (c *Client) getAppNamesFromList(ctx context.Context, appIDs []int) (map[int]string, error) {
+	appList, err := c.currentAppList(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	c.appListMu.RLock()
-	defer c.appListMu.RUnlock()
-
 	out := make(map[int]string, len(appIDs))
 	for _, id := range appIDs {
-		if name, ok := c.appList[id]; ok {
+		if name, ok := appList[id]; ok {
 			out[id] = name
 		}
 	}
 	return out, nil
 }
 
+// currentAppList returns the cached app list, refreshing it once it is older than appListTTL.
+// Concurrent callers share a single refresh; a failed refresh keeps serving the previous list.
+// The returned map is never modified after it is published.
+func (c *Client) currentAppList(ctx context.Context) (map[int]string, error) {
+	c.appListMu.RLock()
+	appList, fetchedAt, retryAt := c.appList, c.appListFetchedAt, c.appListRetryAt
+	c.appListMu.RUnlock()
+
+	now := time.Now()
+	if appList != nil && (now.Sub(fetchedAt) < appListTTL || now.Before(retryAt)) {
+		return appList, nil
+	}
+
+	// The fetch itself is detached from ctx so one cancelled caller doesn't fail the shared refresh.
+	ch := c.appListGroup.DoChan("applist", func() (interface{}, error) {
+		fetched, err := c.fetchFullAppList()
+
+		c.appListMu.Lock()
+		defer c.appListMu.Unlock()
+		if err != nil {
+			c.appListRetryAt = time.Now().Add(appListRetryDelay)
+			return nil, err
+		}
+		c.appList = fetched
+		c.appListFetchedAt = time.Now()
+		return fetched, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			if appList != nil {
+				return appList, nil
+			}
+			return nil, res.Err
+		}
+		return res.Val.(map[int]string), nil
+	}
+}
+
 func (c *Client) fetchFullAppList() (map[int]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
-	endpoint := "https://api.steampowered.com/ISteamApps/GetAppList/v2"
+	endpoint := fmt.Sprintf("%s/ISteamApps/GetAppList/v2", c.apiURL)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", "gamedivers/1.0")
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -572,8 +787,16 @@ func (c *Client) fetchFullAppList() (map[int]string, error) {
 }
 
 // GetOwnedGames retrieves the user's game library
-func (c *Client) GetOwnedGames(steamID string) ([]Game, error) {
-	endpoint := fmt.Sprintf("%s/IPlayerService/GetOwnedGames/v1/", steamAPIURL)
+func (c *Client) GetOwnedGames(ctx context.Context, steamID string) ([]Game, error) {
+	if err := c.requireAPIKey(); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/IPlayerService/GetOwnedGames/v1/", c.apiURL)
 
 	params := url.Values{}
 	params.Set("key", c.apiKey)
@@ -582,7 +805,7 @@ func (c *Client) GetOwnedGames(steamID string) ([]Game, error) {
 	params.Set("include_played_free_games", "1")
 	params.Set("format", "json")
 
-	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build games request")
 	}
@@ -593,13 +816,16 @@ func (c *Client) GetOwnedGames(steamID string) ([]Game, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("%w: steam api error: %d", ErrSteamProfilePrivate, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("steam api error: %d", resp.StatusCode)
 	}
 
 	var result struct {
 		Response struct {
-			GameCount int    `json:"game_count"`
+			GameCount *int   `json:"game_count"`
 			Games     []Game `json:"games"`
 		} `json:"response"`
 	}
@@ -608,19 +834,32 @@ func (c *Client) GetOwnedGames(steamID string) ([]Game, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	// Private libraries come back as an empty "response" object rather than game_count 0.
+	if result.Response.GameCount == nil {
+		return nil, ErrSteamProfilePrivate
+	}
+
 	return result.Response.Games, nil
 }
 
 // GetPlayerSummaries retrieves player profile information
-func (c *Client) GetPlayerSummaries(steamIDs []string) ([]PlayerSummary, error) {
-	endpoint := fmt.Sprintf("%s/ISteamUser/GetPlayerSummaries/v2/", steamAPIURL)
+func (c *Client) GetPlayerSummaries(ctx context.Context, steamIDs []string) ([]PlayerSummary, error) {
+	if err := c.requireAPIKey(); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/ISteamUser/GetPlayerSummaries/v2/", c.apiURL)
 
 	params := url.Values{}
 	params.Set("key", c.apiKey)
 	params.Set("steamids", steamIDs[0]) // For simplicity, just get first one
 	params.Set("format", "json")
 
-	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build player request")
 	}
@@ -631,6 +870,10 @@ func (c *Client) GetPlayerSummaries(steamIDs []string) ([]PlayerSummary, error)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("steam api error: %d", resp.StatusCode)
+	}
+
 	var result struct {
 		Response struct {
 			Players []PlayerSummary `json:"players"`
@@ -1,6 +1,21 @@
 package steam
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"gamedivers.de/api/internal/ports/store"
+)
 
 func TestOpenIDRealmFromReturnURL(t *testing.T) {
 	got := openIDRealmFromReturnURL("https://gamedivers.de/api/v1/steam/callback?state=abc")
@@ -10,3 +25,278 @@ func TestOpenIDRealmFromReturnURL(t *testing.T) {
 	}
 }
 
+func TestGetOwnedGamesPrivateProfile(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "forbidden",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+		},
+		{
+			name: "empty response object",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"response":{}}`))
+			},
+		},
+	} {
+		c := newTestClient(t, tc.handler)
+		if _, err := c.GetOwnedGames(context.Background(), "76561198000000000"); !errors.Is(err, ErrSteamProfilePrivate) {
+			t.Errorf("%s: expected ErrSteamProfilePrivate, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestGetOwnedGamesEmptyLibrary(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":{"game_count":0}}`))
+	})
+
+	games, err := c.GetOwnedGames(context.Background(), "76561198000000000")
+	if err != nil {
+		t.Fatalf("expected no error for an empty public library, got %v", err)
+	}
+	if len(games) != 0 {
+		t.Fatalf("expected no games, got %d", len(games))
+	}
+}
+
+func TestStaleAppListRefreshesOnceUnderConcurrency(t *testing.T) {
+	var hits int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"applist":{"apps":[{"appid":620,"name":"Portal 2"}]}}`))
+	})
+	c.appList = map[int]string{620: "Old Name"}
+	c.appListFetchedAt = time.Now().Add(-2 * appListTTL)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			names, err := c.getAppNamesFromList(context.Background(), []int{620})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if names[620] != "Portal 2" {
+				errs <- fmt.Errorf("expected refreshed name, got %q", names[620])
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected exactly one app list refresh, got %d", got)
+	}
+}
+
+func TestAppListRefreshFailureKeepsStaleList(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	c.appList = map[int]string{620: "Portal 2"}
+	c.appListFetchedAt = time.Now().Add(-2 * appListTTL)
+
+	names, err := c.getAppNamesFromList(context.Background(), []int{620})
+	if err != nil {
+		t.Fatalf("expected stale list on refresh failure, got %v", err)
+	}
+	if names[620] != "Portal 2" {
+		t.Fatalf("expected stale name, got %q", names[620])
+	}
+}
+
+func TestWebAPIRequiresKey(t *testing.T) {
+	c := NewClient("", "")
+
+	if _, err := c.GetOwnedGames(context.Background(), "76561198000000000"); !errors.Is(err, store.ErrProviderNotConfigured) {
+		t.Fatalf("expected ErrProviderNotConfigured, got %v", err)
+	}
+	if _, err := c.GetFriendList(context.Background(), "76561198000000000"); !errors.Is(err, store.ErrProviderNotConfigured) {
+		t.Fatalf("expected ErrProviderNotConfigured, got %v", err)
+	}
+}
+
+func TestGetWishlistUsesWebAPI(t *testing.T) {
+	var wishlistCalls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/IWishlistService/GetWishlist/v1":
+			wishlistCalls.Add(1)
+			if r.URL.Query().Get("steamid") != "76561198000000000" {
+				t.Errorf("unexpected steamid %q", r.URL.Query().Get("steamid"))
+			}
+			_, _ = w.Write([]byte(`{"response":{"items":[{"appid":620,"priority":1,"date_added":1700000000}]}}`))
+		case "/api/appdetails":
+			_, _ = w.Write([]byte(`{"620":{"success":true,"data":{"name":"Portal 2","capsule_image":"cap.jpg"}}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	c.storeURL = c.apiURL
+
+	items, err := c.GetWishlist(context.Background(), "76561198000000000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].AppID != 620 || items[0].Name != "Portal 2" {
+		t.Fatalf("unexpected wishlist %+v", items)
+	}
+
+	// The wishlist is paced by the Web API limiter like the other api.steampowered.com calls.
+	c.apiLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	c.apiLimiter.Allow()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetWishlist(ctx, "76561198000000000"); err == nil {
+		t.Fatal("expected the exhausted Web API limiter to hold the wishlist call")
+	}
+	if wishlistCalls.Load() != 1 {
+		t.Fatalf("expected 1 wishlist request, got %d", wishlistCalls.Load())
+	}
+}
+
+func newTestPriceClient(t *testing.T, calls *atomic.Int32) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/api/appdetails" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("cc") {
+		case "de":
+			_, _ = w.Write([]byte(`{"620":{"success":true,"data":{"name":"Portal 2","price_overview":{"currency":"EUR","initial":819,"final":164,"discount_percent":80}}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"620":{"success":true,"data":{"name":"Portal 2","price_overview":{"currency":"USD","initial":999,"final":199,"discount_percent":80}}}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := New()
+	c.storeURL = server.URL
+	c.limiter = nil
+	return c
+}
+
+func TestFetchPriceUsesRegion(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestPriceClient(t, &calls)
+
+	price, name, err := c.FetchPrice(context.Background(), "620", "DE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "Portal 2" || price == nil || price.Currency != "EUR" || price.FinalCents != 164 {
+		t.Fatalf("expected EUR pricing for cc=de, got %+v (%q)", price, name)
+	}
+
+	price, _, err = c.FetchPrice(context.Background(), "620", "us")
+	if err != nil || price == nil || price.Currency != "USD" {
+		t.Fatalf("expected USD pricing for cc=us, got %+v (%v)", price, err)
+	}
+
+	// Same (appid, region) again is served from cache.
+	if _, _, err := c.FetchPrice(context.Background(), "620", "de"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestFetchPriceWithoutCacheRefetches(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestPriceClient(t, &calls)
+	ctx := context.Background()
+
+	if _, _, err := c.FetchPrice(ctx, "620", "de"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := c.FetchPrice(store.WithoutCache(ctx), "620", "de"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the forced fetch to reach Steam, got %d upstream calls", calls.Load())
+	}
+}
+
+func TestFetchPriceEvictsExpiredEntries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestPriceClient(t, &calls)
+	c.priceCache["440|de"] = cachedPrice{fetchedAt: time.Now().Add(-2 * priceCacheTTL)}
+
+	if _, _, err := c.FetchPrice(context.Background(), "620", "de"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := c.priceCache["440|de"]; ok {
+		t.Fatal("expected the expired entry to be evicted")
+	}
+	if _, ok := c.priceCache["620|de"]; !ok {
+		t.Fatal("expected the fresh price to be cached")
+	}
+}
+
+func TestFetchPriceRejectsInvalidRegion(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestPriceClient(t, &calls)
+
+	for _, cc := range []string{"", "deu", "d1", "de&cc=us"} {
+		if _, _, err := c.FetchPrice(context.Background(), "620", cc); !errors.Is(err, ErrInvalidRegion) {
+			t.Errorf("cc %q: expected ErrInvalidRegion, got %v", cc, err)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("expected no upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestAppMetadataBatchRetriesFailedChunk(t *testing.T) {
+	var failures atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("appids"), ",")
+		if r.URL.Query().Get("appids") == "30,40" && failures.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		entries := make([]string, 0, len(ids))
+		for _, id := range ids {
+			entries = append(entries, `"`+id+`":{"success":true,"data":{"name":"App `+id+`","capsule_image":"cap-`+id+`.jpg"}}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{" + strings.Join(entries, ",") + "}"))
+	}))
+	t.Cleanup(server.Close)
+
+	c := New()
+	c.storeURL = server.URL
+	c.limiter = nil
+
+	out := c.getAppMetadataBatch(context.Background(), []int{10, 20, 30, 40, 50}, 2)
+
+	if failures.Load() != 2 {
+		t.Fatalf("expected the failing chunk to be retried once, got %d attempts", failures.Load())
+	}
+	for _, id := range []int{10, 20, 30, 40, 50} {
+		want := fmt.Sprintf("App %d", id)
+		if out[id].Name != want || out[id].Capsule != fmt.Sprintf("cap-%d.jpg", id) {
+			t.Fatalf("app %d: got %+v, want name %q", id, out[id], want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package steam
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// appDetailsTripAfter consecutive 429s open the breaker.
+	appDetailsTripAfter = 3
+	// The first trip pauses appdetails for appDetailsBaseCooldown; each failed probe doubles it up to appDetailsMaxCooldown.
+	appDetailsBaseCooldown = time.Minute
+	appDetailsMaxCooldown  = 15 * time.Minute
+)
+
+// ErrAppDetailsThrottled is returned instead of calling appdetails while Steam is rate limiting us.
+var ErrAppDetailsThrottled = errors.New("steam appdetails paused after repeated rate limiting")
+
+// BreakerState describes the appdetails circuit breaker for metrics.
+type BreakerState struct {
+	Open                  bool       `json:"open"`
+	OpenUntil             *time.Time `json:"openUntil,omitempty"`
+	ConsecutiveRateLimits int        `json:"consecutiveRateLimits"`
+	Trips                 int64      `json:"trips"`
+}
+
+// AppDetailsState reports the appdetails breaker this client uses.
+func (c *Client) AppDetailsState() BreakerState {
+	return c.appDetails.state()
+}
+
+// ShareAppDetailsBreaker makes c use other's appdetails breaker. Steam throttles appdetails
+// per IP, so clients in one process should trip and recover together.
+func (c *Client) ShareAppDetailsBreaker(other *Client) {
+	c.appDetails = other.appDetails
+}
+
+type appDetailsBreaker struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	consecutive int
+	cooldown    time.Duration
+	openUntil   time.Time
+	trips       int64
+}
+
+func newAppDetailsBreaker() *appDetailsBreaker {
+	return &appDetailsBreaker{now: time.Now}
+}
+
+// allow reports ErrAppDetailsThrottled while the breaker is open. Once the cooldown has
+// passed calls go through again; the next 429 re-opens it with a longer cooldown.
+func (b *appDetailsBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.now().Before(b.openUntil) {
+		return ErrAppDetailsThrottled
+	}
+	return nil
+}
+
+// record updates the breaker with an appdetails response status.
+func (b *appDetailsBreaker) record(status int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if status != http.StatusTooManyRequests {
+		b.consecutive = 0
+		b.cooldown = 0
+		return
+	}
+
+	now := b.now()
+	if now.Before(b.openUntil) {
+		// A request that was already in flight when the breaker opened.
+		return
+	}
+
+	b.consecutive++
+	if b.consecutive < appDetailsTripAfter {
+		return
+	}
+
+	b.cooldown = min(max(b.cooldown*2, appDetailsBaseCooldown), appDetailsMaxCooldown)
+	b.openUntil = now.Add(b.cooldown)
+	b.trips++
+	log.Printf("steam: appdetails rate limited %d times in a row, pausing for %s", b.consecutive, b.cooldown)
+}
+
+func (b *appDetailsBreaker) state() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := BreakerState{ConsecutiveRateLimits: b.consecutive, Trips: b.trips}
+	if b.now().Before(b.openUntil) {
+		until := b.openUntil
+		s.Open = true
+		s.OpenUntil = &until
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAppDetailsBreakerTripsOnRepeated429(t *testing.T) {
+	var hits atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusTooManyRequests)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		_, _ = w.Write([]byte(`{"620":{"success":true,"data":{"name":"Portal 2"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Unix(1700000000, 0)
+	c := New()
+	c.storeURL = server.URL
+	c.limiter = nil
+	c.appDetails = &appDetailsBreaker{now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	for range appDetailsTripAfter {
+		if _, err := c.fetchAppDetailsChunk(ctx, []int{620}); err == nil || errors.Is(err, ErrAppDetailsThrottled) {
+			t.Fatalf("expected a 429 error before the breaker opens, got %v", err)
+		}
+	}
+
+	state := c.appDetails.state()
+	if !state.Open || state.Trips != 1 || !state.OpenUntil.Equal(now.Add(appDetailsBaseCooldown)) {
+		t.Fatalf("expected the breaker to open for the base cooldown, got %+v", state)
+	}
+
+	// While open, neither appdetails path reaches Steam.
+	if _, err := c.fetchAppDetailsChunk(ctx, []int{620}); !errors.Is(err, ErrAppDetailsThrottled) {
+		t.Fatalf("expected ErrAppDetailsThrottled, got %v", err)
+	}
+	if _, _, err := c.FetchPrice(ctx, "620", "de"); !errors.Is(err, ErrAppDetailsThrottled) {
+		t.Fatalf("expected FetchPrice to be paused too, got %v", err)
+	}
+	if hits.Load() != appDetailsTripAfter {
+		t.Fatalf("expected %d requests, got %d", appDetailsTripAfter, hits.Load())
+	}
+
+	// A failed probe after the cooldown re-opens it for twice as long.
+	now = now.Add(appDetailsBaseCooldown)
+	if _, err := c.fetchAppDetailsChunk(ctx, []int{620}); errors.Is(err, ErrAppDetailsThrottled) {
+		t.Fatalf("expected a probe request after the cooldown, got %v", err)
+	}
+	if state := c.appDetails.state(); !state.Open || state.Trips != 2 || !state.OpenUntil.Equal(now.Add(2*appDetailsBaseCooldown)) {
+		t.Fatalf("expected a doubled cooldown, got %+v", state)
+	}
+
+	// Once Steam answers again the breaker closes and resets.
+	now = now.Add(2 * appDetailsBaseCooldown)
+	status.Store(http.StatusOK)
+	metadata, err := c.fetchAppDetailsChunk(ctx, []int{620})
+	if err != nil || metadata[620].Name != "Portal 2" {
+		t.Fatalf("expected appdetails to resume, got %+v (%v)", metadata, err)
+	}
+	if state := c.appDetails.state(); state.Open || state.ConsecutiveRateLimits != 0 {
+		t.Fatalf("expected a closed breaker, got %+v", state)
+	}
+}
+
+func TestAppDetailsBreakerIsPerClientUnlessShared(t *testing.T) {
+	a, b, c := New(), New(), New()
+	c.ShareAppDetailsBreaker(a)
+
+	for range appDetailsTripAfter {
+		a.appDetails.record(http.StatusTooManyRequests)
+	}
+
+	if !a.AppDetailsState().Open || !c.AppDetailsState().Open {
+		t.Fatal("expected the trip to pause both clients sharing the breaker")
+	}
+	if b.AppDetailsState().Open {
+		t.Fatal("expected an unrelated client to keep its own breaker")
+	}
+}
@@ -0,0 +1,228 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// friendsOwningWorkers bounds concurrent library fetches; the Web API limiter still paces them.
+	friendsOwningWorkers = 4
+	// A friends-owning count costs one library fetch per friend, so results are reused for a while.
+	friendsOwningCacheTTL        = 15 * time.Minute
+	friendsOwningCacheMaxEntries = 1000
+)
+
+type cachedFriendsOwning struct {
+	result    *FriendsOwning
+	fetchedAt time.Time
+}
+
+// Friend is an entry of a user's Steam friend list
+type Friend struct {
+	SteamID      string `json:"steamid"`
+	Relationship string `json:"relationship"`
+	FriendSince  int64  `json:"friend_since"`
+}
+
+// FriendsOwningGame is a game from the user's library with the number of queried friends who own it
+type FriendsOwningGame struct {
+	AppID         int    `json:"appId"`
+	Name          string `json:"name"`
+	FriendsOwning int    `json:"friendsOwning"`
+}
+
+// FriendsOwning summarizes co-ownership of the user's library among their friends
+type FriendsOwning struct {
+	FriendsTotal   int                 `json:"friendsTotal"`
+	FriendsQueried int                 `json:"friendsQueried"`
+	FriendsSkipped int                 `json:"friendsSkipped"`
+	Games          []FriendsOwningGame `json:"games"`
+}
+
+// GetFriendList retrieves the user's friend list.
+// Steam answers 401 when the friend list is not public.
+func (c *Client) GetFriendList(ctx context.Context, steamID string) ([]Friend, error) {
+	if err := c.requireAPIKey(); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/ISteamUser/GetFriendList/v1/", c.apiURL)
+
+	params := url.Values{}
+	params.Set("key", c.apiKey)
+	params.Set("steamid", steamID)
+	params.Set("relationship", "friend")
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build friend list request")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch friend list")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("%w: steam api error: %d", ErrSteamProfilePrivate, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("steam api error: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		FriendsList struct {
+			Friends []Friend `json:"friends"`
+		} `json:"friendslist"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.FriendsList.Friends, nil
+}
+
+// CountFriendsOwning counts, for each game in the user's library, how many friends also own it.
+// At most maxFriends friends are queried; friends whose libraries cannot be read are skipped.
+// Results are cached per user for friendsOwningCacheTTL and must not be modified by callers.
+func (c *Client) CountFriendsOwning(ctx context.Context, steamID string, maxFriends int) (*FriendsOwning, error) {
+	key := steamID + "|" + strconv.Itoa(maxFriends)
+	c.friendsMu.Lock()
+	cached, ok := c.friendsOwning[key]
+	c.friendsMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < friendsOwningCacheTTL {
+		return cached.result, nil
+	}
+
+	result, err := c.countFriendsOwning(ctx, steamID, maxFriends)
+	if err != nil {
+		return nil, err
+	}
+	c.cacheFriendsOwning(key, result)
+	return result, nil
+}
+
+// cacheFriendsOwning stores a result, dropping expired entries when the cache is full.
+func (c *Client) cacheFriendsOwning(key string, result *FriendsOwning) {
+	now := time.Now()
+
+	c.friendsMu.Lock()
+	defer c.friendsMu.Unlock()
+
+	if c.friendsOwning == nil {
+		c.friendsOwning = map[string]cachedFriendsOwning{}
+	}
+	if len(c.friendsOwning) >= friendsOwningCacheMaxEntries {
+		for k, existing := range c.friendsOwning {
+			if now.Sub(existing.fetchedAt) >= friendsOwningCacheTTL {
+				delete(c.friendsOwning, k)
+			}
+		}
+	}
+	if _, exists := c.friendsOwning[key]; !exists && len(c.friendsOwning) >= friendsOwningCacheMaxEntries {
+		return
+	}
+	c.friendsOwning[key] = cachedFriendsOwning{result: result, fetchedAt: now}
+}
+
+func (c *Client) countFriendsOwning(ctx context.Context, steamID string, maxFriends int) (*FriendsOwning, error) {
+	library, err := c.GetOwnedGames(ctx, steamID)
+	if err != nil {
+		return nil, err
+	}
+	friends, err := c.GetFriendList(ctx, steamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the longest-standing friends first so the capped subset is stable between calls.
+	sort.Slice(friends, func(i, j int) bool {
+		if friends[i].FriendSince != friends[j].FriendSince {
+			return friends[i].FriendSince < friends[j].FriendSince
+		}
+		return friends[i].SteamID < friends[j].SteamID
+	})
+	queried := friends
+	if maxFriends > 0 && len(queried) > maxFriends {
+		queried = queried[:maxFriends]
+	}
+
+	owned := make(map[int]int, len(library))
+	for _, game := range library {
+		owned[game.AppID] = 0
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		skipped int
+		jobs    = make(chan string)
+	)
+	for range min(friendsOwningWorkers, len(queried)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for friendID := range jobs {
+				games, err := c.GetOwnedGames(ctx, friendID)
+
+				mu.Lock()
+				if err != nil {
+					skipped++
+				}
+				for _, game := range games {
+					if count, ok := owned[game.AppID]; ok {
+						owned[game.AppID] = count + 1
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, friend := range queried {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- friend.SteamID
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &FriendsOwning{
+		FriendsTotal:   len(friends),
+		FriendsQueried: len(queried),
+		FriendsSkipped: skipped,
+		Games:          []FriendsOwningGame{},
+	}
+	for _, game := range library {
+		if count := owned[game.AppID]; count > 0 {
+			result.Games = append(result.Games, FriendsOwningGame{AppID: game.AppID, Name: game.Name, FriendsOwning: count})
+		}
+	}
+	sort.Slice(result.Games, func(i, j int) bool {
+		if result.Games[i].FriendsOwning != result.Games[j].FriendsOwning {
+			return result.Games[i].FriendsOwning > result.Games[j].FriendsOwning
+		}
+		return result.Games[i].AppID < result.Games[j].AppID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestGetFriendListPrivate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	if _, err := c.GetFriendList(context.Background(), "76561198000000000"); !errors.Is(err, ErrSteamProfilePrivate) {
+		t.Fatalf("expected ErrSteamProfilePrivate, got %v", err)
+	}
+}
+
+func TestCountFriendsOwning(t *testing.T) {
+	libraries := map[string]string{
+		"1": `{"response":{"game_count":3,"games":[{"appid":220,"name":"Half-Life 2"},{"appid":400,"name":"Portal"},{"appid":620,"name":"Portal 2"}]}}`,
+		"2": `{"response":{"game_count":2,"games":[{"appid":220},{"appid":620}]}}`,
+		"3": `{"response":{"game_count":2,"games":[{"appid":620},{"appid":730}]}}`,
+		"4": `{"response":{}}`,
+		"5": `{"response":{"game_count":1,"games":[{"appid":400}]}}`,
+	}
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ISteamUser/GetFriendList/v1/":
+			_, _ = w.Write([]byte(`{"friendslist":{"friends":[
+				{"steamid":"5","relationship":"friend","friend_since":500},
+				{"steamid":"3","relationship":"friend","friend_since":300},
+				{"steamid":"2","relationship":"friend","friend_since":200},
+				{"steamid":"4","relationship":"friend","friend_since":400}]}}`))
+		case "/IPlayerService/GetOwnedGames/v1/":
+			_, _ = w.Write([]byte(libraries[r.URL.Query().Get("steamid")]))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	result, err := c.CountFriendsOwning(context.Background(), "1", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Friend 5 is the newest and falls outside the cap; friend 4 has a private library.
+	if result.FriendsTotal != 4 || result.FriendsQueried != 3 || result.FriendsSkipped != 1 {
+		t.Fatalf("unexpected friend counts %+v", result)
+	}
+	if len(result.Games) != 2 {
+		t.Fatalf("expected 2 co-owned games, got %+v", result.Games)
+	}
+	if result.Games[0].AppID != 620 || result.Games[0].FriendsOwning != 2 || result.Games[0].Name != "Portal 2" {
+		t.Fatalf("unexpected first game %+v", result.Games[0])
+	}
+	if result.Games[1].AppID != 220 || result.Games[1].FriendsOwning != 1 {
+		t.Fatalf("unexpected second game %+v", result.Games[1])
+	}
+
+	// A repeat request for the same user is answered from the cache.
+	before := requests.Load()
+	if _, err := c.CountFriendsOwning(context.Background(), "1", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests.Load() != before {
+		t.Fatalf("expected a cached result, got %d more requests", requests.Load()-before)
+	}
+}
+
+func TestWebAPINotBlockedByStoreLimiter(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"response":{"game_count":0}}`))
+	})
+	// An exhausted store limiter (appdetails, prices) must not stall library calls.
+	c.limiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	c.limiter.Allow()
+	c.apiLimiter = rate.NewLimiter(webAPIRate, webAPIBurst)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := c.GetOwnedGames(ctx, "1"); err != nil {
+		t.Fatalf("expected the library call to use the Web API limiter, got %v", err)
+	}
+}
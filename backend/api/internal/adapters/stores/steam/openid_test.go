@@ -0,0 +1,86 @@
+package steam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestOpenIDClient(t *testing.T, isValid string, calls *atomic.Int32) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("openid.mode") != "check_authentication" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:" + isValid + "\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient("test-key", "")
+	c.openIDURL = server.URL
+	return c
+}
+
+func openIDCallbackValues(claimedID string) url.Values {
+	values := url.Values{}
+	values.Set("openid.ns", "http://specs.openid.net/auth/2.0")
+	values.Set("openid.mode", "id_res")
+	values.Set("openid.op_endpoint", steamOpenIDURL)
+	values.Set("openid.claimed_id", claimedID)
+	values.Set("openid.identity", claimedID)
+	values.Set("openid.sig", "signature")
+	return values
+}
+
+func TestVerifyCallbackAcceptsConfirmedAssertion(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestOpenIDClient(t, "true", &calls)
+
+	steamID, err := c.VerifyCallback(openIDCallbackValues("https://steamcommunity.com/openid/id/76561198000000000"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steamID != "76561198000000000" {
+		t.Fatalf("expected steam id 76561198000000000, got %q", steamID)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected one check_authentication call, got %d", calls.Load())
+	}
+}
+
+func TestVerifyCallbackRejectsForgedAssertion(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestOpenIDClient(t, "false", &calls)
+
+	if _, err := c.VerifyCallback(openIDCallbackValues("https://steamcommunity.com/openid/id/76561198000000000")); err == nil {
+		t.Fatal("expected forged assertion to be rejected")
+	}
+}
+
+func TestVerifyCallbackRejectsMalformedAssertion(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestOpenIDClient(t, "true", &calls)
+
+	mismatched := openIDCallbackValues("https://steamcommunity.com/openid/id/76561198000000000")
+	mismatched.Set("openid.identity", "https://steamcommunity.com/openid/id/76561198000000001")
+
+	otherEndpoint := openIDCallbackValues("https://steamcommunity.com/openid/id/76561198000000000")
+	otherEndpoint.Set("openid.op_endpoint", "https://evil.example/openid/login")
+
+	for name, values := range map[string]url.Values{
+		"identity mismatch": mismatched,
+		"foreign endpoint":  otherEndpoint,
+		"bad claimed id":    openIDCallbackValues("https://evil.example/openid/id/76561198000000000"),
+	} {
+		if _, err := c.VerifyCallback(values); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("malformed assertions should not reach Steam, got %d calls", calls.Load())
+	}
+}
@@ -0,0 +1,109 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrPlayerNotFound is returned when Steam has no profile for the requested SteamID.
+var ErrPlayerNotFound = errors.New("steam player not found")
+
+// Profile is a player's public Steam profile with badge-relevant details.
+// Level and TimeCreated are nil when the profile hides them.
+type Profile struct {
+	SteamID     string `json:"steamId"`
+	PersonaName string `json:"personaName"`
+	ProfileURL  string `json:"profileUrl"`
+	Avatar      string `json:"avatar"`
+	Level       *int   `json:"level"`
+	TimeCreated *int64 `json:"timeCreated"`
+}
+
+// GetSteamLevel retrieves the player's Steam level.
+// Private profiles come back as an empty "response" object; found is false then.
+func (c *Client) GetSteamLevel(ctx context.Context, steamID string) (level int, found bool, err error) {
+	if err := c.requireAPIKey(); err != nil {
+		return 0, false, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return 0, false, err
+	}
+
+	endpoint := fmt.Sprintf("%s/IPlayerService/GetSteamLevel/v1/", c.apiURL)
+
+	params := url.Values{}
+	params.Set("key", c.apiKey)
+	params.Set("steamid", steamID)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to build steam level request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch steam level")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return 0, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("steam api error: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Response struct {
+			PlayerLevel *int `json:"player_level"`
+		} `json:"response"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if result.Response.PlayerLevel == nil {
+		return 0, false, nil
+	}
+	return *result.Response.PlayerLevel, true, nil
+}
+
+// GetProfile combines the player's summary with their Steam level.
+func (c *Client) GetProfile(ctx context.Context, steamID string) (*Profile, error) {
+	players, err := c.GetPlayerSummaries(ctx, []string{steamID})
+	if err != nil {
+		return nil, err
+	}
+	if len(players) == 0 {
+		return nil, ErrPlayerNotFound
+	}
+	player := players[0]
+
+	profile := &Profile{
+		SteamID:     player.SteamID,
+		PersonaName: player.PersonaName,
+		ProfileURL:  player.ProfileURL,
+		Avatar:      player.AvatarFull,
+	}
+	if player.TimeCreated > 0 {
+		created := player.TimeCreated
+		profile.TimeCreated = &created
+	}
+
+	level, found, err := c.GetSteamLevel(ctx, steamID)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		profile.Level = &level
+	}
+
+	return profile, nil
+}
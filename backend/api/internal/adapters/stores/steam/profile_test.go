@@ -0,0 +1,69 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetProfilePublic(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ISteamUser/GetPlayerSummaries/v2/":
+			_, _ = w.Write([]byte(`{"response":{"players":[{"steamid":"1","personaname":"Gordon","communityvisibilitystate":3,"timecreated":1100000000,"avatarfull":"full.jpg"}]}}`))
+		case "/IPlayerService/GetSteamLevel/v1/":
+			_, _ = w.Write([]byte(`{"response":{"player_level":42}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	profile, err := c.GetProfile(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.PersonaName != "Gordon" || profile.Avatar != "full.jpg" {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+	if profile.Level == nil || *profile.Level != 42 {
+		t.Fatalf("expected level 42, got %v", profile.Level)
+	}
+	if profile.TimeCreated == nil || *profile.TimeCreated != 1100000000 {
+		t.Fatalf("expected timecreated 1100000000, got %v", profile.TimeCreated)
+	}
+}
+
+func TestGetProfilePrivate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ISteamUser/GetPlayerSummaries/v2/":
+			_, _ = w.Write([]byte(`{"response":{"players":[{"steamid":"1","personaname":"Private","communityvisibilitystate":1}]}}`))
+		case "/IPlayerService/GetSteamLevel/v1/":
+			_, _ = w.Write([]byte(`{"response":{}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	profile, err := c.GetProfile(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.Level != nil || profile.TimeCreated != nil {
+		t.Fatalf("expected no level or creation time, got %+v", profile)
+	}
+}
+
+func TestGetProfileNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":{"players":[]}}`))
+	})
+
+	if _, err := c.GetProfile(context.Background(), "1"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Fatalf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// RecentGame is a game played in the last two weeks
+type RecentGame struct {
+	AppID           int    `json:"appid"`
+	Name            string `json:"name"`
+	Playtime2Weeks  int    `json:"playtime_2weeks"` // minutes
+	PlaytimeForever int    `json:"playtime_forever"`
+	ImgIconURL      string `json:"img_icon_url"`
+}
+
+// GetRecentlyPlayedGames retrieves the games played in the last two weeks.
+// Steam applies its default count, so this is always a single request.
+func (c *Client) GetRecentlyPlayedGames(ctx context.Context, steamID string) ([]RecentGame, error) {
+	if err := c.requireAPIKey(); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitWebAPI(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/IPlayerService/GetRecentlyPlayedGames/v1/", c.apiURL)
+
+	params := url.Values{}
+	params.Set("key", c.apiKey)
+	params.Set("steamid", steamID)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build recently played request")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recently played games")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("%w: steam api error: %d", ErrSteamProfilePrivate, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("steam api error: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Response struct {
+			TotalCount *int         `json:"total_count"`
+			Games      []RecentGame `json:"games"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Like GetOwnedGames, private profiles come back as an empty "response" object.
+	if result.Response.TotalCount == nil {
+		return nil, ErrSteamProfilePrivate
+	}
+
+	return result.Response.Games, nil
+}
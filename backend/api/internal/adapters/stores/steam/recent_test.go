@@ -0,0 +1,39 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetRecentlyPlayedGames(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("count") != "" {
+			t.Errorf("expected Steam's default count, got count=%q", r.URL.Query().Get("count"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":{"total_count":2,"games":[
+			{"appid":620,"name":"Portal 2","playtime_2weeks":95,"playtime_forever":1200},
+			{"appid":440,"name":"Team Fortress 2","playtime_2weeks":30,"playtime_forever":5000}]}}`))
+	})
+
+	games, err := c.GetRecentlyPlayedGames(context.Background(), "76561198000000000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(games) != 2 || games[0].AppID != 620 || games[0].Playtime2Weeks != 95 {
+		t.Fatalf("unexpected games %+v", games)
+	}
+}
+
+func TestGetRecentlyPlayedGamesPrivateProfile(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":{}}`))
+	})
+
+	if _, err := c.GetRecentlyPlayedGames(context.Background(), "76561198000000000"); !errors.Is(err, ErrSteamProfilePrivate) {
+		t.Fatalf("expected ErrSteamProfilePrivate, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Package storehttp builds the outbound HTTP clients shared by the store integrations.
+package storehttp
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// UserAgent is sent with every store request that doesn't set its own.
+const UserAgent = "gamedivers/1.0"
+
+// NewClient returns an HTTP client for the given store ("steam", "epic", "itad", ...).
+// Requests go through <STORE>_PROXY_URL when set, otherwise through the standard
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment.
+func NewClient(store string, timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc(os.Getenv(proxyEnvKey(store)))
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &userAgentTransport{base: transport},
+	}
+}
+
+// proxyEnvKey returns the per-store proxy variable, e.g. STEAM_PROXY_URL.
+func proxyEnvKey(store string) string {
+	return strings.ToUpper(store) + "_PROXY_URL"
+}
+
+func proxyFunc(storeProxy string) func(*http.Request) (*url.URL, error) {
+	storeProxy = strings.TrimSpace(storeProxy)
+	if storeProxy == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyURL, err := url.Parse(storeProxy)
+	if err != nil || proxyURL.Host == "" {
+		// A broken override must not silently bypass the proxy the operator asked for.
+		return func(*http.Request) (*url.URL, error) {
+			return nil, &url.Error{Op: "proxy", URL: storeProxy, Err: errInvalidProxy}
+		}
+	}
+	return http.ProxyURL(proxyURL)
+}
+
+var errInvalidProxy = errors.New("invalid store proxy url")
+
+type userAgentTransport struct {
+	base http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", UserAgent)
+	return t.base.RoundTrip(req)
+}
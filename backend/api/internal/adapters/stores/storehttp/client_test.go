@@ -0,0 +1,62 @@
+package storehttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClientUsesStoreProxy(t *testing.T) {
+	var gotURL, gotUA string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		gotUA = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	t.Setenv("STEAM_PROXY_URL", proxy.URL)
+	client := NewClient("steam", 5*time.Second)
+
+	resp, err := client.Get("http://store.steampowered.example/api/appdetails?appids=220")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotURL != "http://store.steampowered.example/api/appdetails?appids=220" {
+		t.Fatalf("expected request to go through the proxy, proxy saw %q", gotURL)
+	}
+	if gotUA != UserAgent {
+		t.Fatalf("expected User-Agent %q, got %q", UserAgent, gotUA)
+	}
+}
+
+func TestNewClientKeepsExplicitUserAgent(t *testing.T) {
+	var gotUA string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	t.Setenv("EPIC_PROXY_URL", "")
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("User-Agent", "custom/2.0")
+	resp, err := NewClient("epic", 5*time.Second).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotUA != "custom/2.0" {
+		t.Fatalf("expected explicit User-Agent to be kept, got %q", gotUA)
+	}
+}
+
+func TestNewClientRejectsInvalidStoreProxy(t *testing.T) {
+	t.Setenv("ITAD_PROXY_URL", "://not a url")
+	if _, err := NewClient("itad", time.Second).Get("http://api.isthereanydeal.example/"); err == nil {
+		t.Fatal("expected an invalid proxy override to fail requests instead of bypassing it")
+	}
+}
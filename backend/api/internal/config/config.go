@@ -3,9 +3,17 @@ package config
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 )
 
+// defaultCORSOrigins covers local development: the web/Tauri dev server (vite on :3000, the
+// Tauri devUrl) and the packaged Tauri webview origins.
+const defaultCORSOrigins = "http://localhost:3000,tauri://localhost,http://tauri.localhost"
+
+const defaultMaxBodyBytes = 1 << 20
+
 type Config struct {
 	Port string
 
@@ -14,6 +22,11 @@ type Config struct {
 
 	// Frontend origin for CORS/callbacks
 	FrontendOrigin string
+	// Additional origins allowed to make credentialed cross-origin requests
+	CORSOrigins []string
+
+	// Default ISO 3166-1 alpha-2 country used for price lookups
+	DefaultCountry string
 
 	// Optional Postgres connection string. When set, persistence is enabled.
 	DatabaseURL string
@@ -28,6 +41,28 @@ type Config struct {
 	EpicClientID     string
 	EpicClientSecret string
 	EpicCallbackURL  string
+	// Epic API request rate in requests per second and burst (0 = client defaults)
+	EpicRateLimit float64
+	EpicRateBurst int
+
+	// Add HowLongToBeat completion estimates to game details
+	HLTBEnabled bool
+
+	// Hours after which a tracked game's stored price is refreshed in the background
+	PriceStaleHours int
+
+	// Largest accepted request body in bytes
+	MaxBodyBytes int
+
+	// Directories game executables may be launched from (empty = GOG Galaxy defaults)
+	GameRoots []string
+	// Steam client install directories scanned for installed games (empty = per-OS defaults)
+	SteamRoots []string
+
+	// Password policy for registration and password changes
+	PasswordMinLength  int
+	PasswordRequireMix bool
+
 	// Keycloak configuration
 	KeycloakURL                  string
 	KeycloakRealm                string
@@ -43,12 +78,25 @@ func Load() Config {
 	if frontendOrigin == "" {
 		frontendOrigin = "http://localhost:3000"
 	}
+	corsOrigins := getenvCSV("CORS_ORIGINS", defaultCORSOrigins)
+	defaultCountry := strings.ToUpper(strings.TrimSpace(getenv("DEFAULT_COUNTRY", "DE")))
 	databaseURL := getenv("DATABASE_URL", "")
 	steamAPIKey := getenv("STEAM_API_KEY", "")
 	steamCallbackURL := getenv("STEAM_CALLBACK_URL", "http://localhost:8080/v1/steam/callback")
 	epicClientID := getenv("EPIC_CLIENT_ID", "")
 	epicClientSecret := getenv("EPIC_CLIENT_SECRET", "")
 	epicCallbackURL := getenv("EPIC_CALLBACK_URL", "http://localhost:8080/v1/epic/callback")
+	epicRateLimit := getenvFloat("EPIC_RATE_LIMIT", 0)
+	epicRateBurst := getenvInt("EPIC_RATE_BURST", 0)
+	hltbEnabled := getenvBool("HLTB_ENABLED", false)
+	priceStaleHours := getenvInt("PRICE_STALE_HOURS", 24)
+	maxBodyBytes := getenvInt("MAX_BODY_BYTES", defaultMaxBodyBytes)
+	if maxBodyBytes == 0 {
+		log.Printf("MAX_BODY_BYTES must be positive, using default %d", defaultMaxBodyBytes)
+		maxBodyBytes = defaultMaxBodyBytes
+	}
+	gameRoots := getenvList("GAME_ROOTS")
+	steamRoots := getenvList("STEAM_ROOTS")
 
 	// Keycloak config
 	keycloakURL := mustGetenv("KEYCLOAK_URL")
@@ -56,17 +104,30 @@ func Load() Config {
 	keycloakClientID := mustGetenv("KEYCLOAK_CLIENT_ID")
 	keycloakClientSecret := mustGetenv("KEYCLOAK_CLIENT_SECRET")
 	keycloakRequireEmailVerified := getenvBool("KEYCLOAK_REQUIRE_EMAIL_VERIFIED", true)
+	passwordMinLength := getenvInt("PASSWORD_MIN_LENGTH", 8)
+	passwordRequireMix := getenvBool("PASSWORD_REQUIRE_MIX", false)
 
 	return Config{
 		Port:                         port,
 		ITADAPIKey:                   itadAPIKey,
 		FrontendOrigin:               frontendOrigin,
+		CORSOrigins:                  corsOrigins,
+		DefaultCountry:               defaultCountry,
 		DatabaseURL:                  databaseURL,
 		SteamAPIKey:                  steamAPIKey,
 		SteamCallbackURL:             steamCallbackURL,
 		EpicClientID:                 epicClientID,
 		EpicClientSecret:             epicClientSecret,
 		EpicCallbackURL:              epicCallbackURL,
+		EpicRateLimit:                epicRateLimit,
+		EpicRateBurst:                epicRateBurst,
+		HLTBEnabled:                  hltbEnabled,
+		PriceStaleHours:              priceStaleHours,
+		MaxBodyBytes:                 maxBodyBytes,
+		GameRoots:                    gameRoots,
+		SteamRoots:                   steamRoots,
+		PasswordMinLength:            passwordMinLength,
+		PasswordRequireMix:           passwordRequireMix,
 		KeycloakURL:                  keycloakURL,
 		KeycloakRealm:                keycloakRealm,
 		KeycloakClientID:             keycloakClientID,
@@ -104,3 +165,55 @@ func getenvBool(key string, def bool) bool {
 
 	return parsed
 }
+
+func getenvFloat(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	parsed, err := strconv.ParseFloat(v, 64)
+	if err != nil || parsed < 0 {
+		log.Printf("invalid number for %s, using default %g", key, def)
+		return def
+	}
+
+	return parsed
+}
+
+func getenvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed < 0 {
+		log.Printf("invalid integer for %s, using default %d", key, def)
+		return def
+	}
+
+	return parsed
+}
+
+// getenvCSV splits a comma-separated variable, falling back to def when unset.
+func getenvCSV(key, def string) []string {
+	var out []string
+	for _, part := range strings.Split(getenv(key, def), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// getenvList splits an OS path-list style variable (";" on Windows, ":" elsewhere).
+func getenvList(key string) []string {
+	var out []string
+	for _, part := range filepath.SplitList(os.Getenv(key)) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
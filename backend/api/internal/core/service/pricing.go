@@ -15,14 +15,15 @@ type PricingService struct {
 	NowUnix func() int64
 }
 
-func (s *PricingService) EnsureSteamDEPriceFresh(ctx context.Context, appid string, force bool) error {
+// EnsureSteamPriceFresh refetches the app's price in region cc (lower-case) unless the stored one is younger than TTL.
+func (s *PricingService) EnsureSteamPriceFresh(ctx context.Context, appid, cc string, force bool) error {
 	now := s.NowUnix()
 
-	if err := s.Repo.TrackGame(ctx, "steam", appid, "de", now); err != nil {
+	if err := s.Repo.TrackGame(ctx, "steam", appid, cc, now); err != nil {
 		return err
 	}
 
-	fetchedAt, found, err := s.Repo.GetPriceFetchedAt(ctx, "steam", appid, "de")
+	fetchedAt, found, err := s.Repo.GetPriceFetchedAt(ctx, "steam", appid, cc)
 	if err != nil {
 		return err
 	}
@@ -32,8 +33,11 @@ func (s *PricingService) EnsureSteamDEPriceFresh(ctx context.Context, appid stri
 			return nil
 		}
 	}
+	if force {
+		ctx = store.WithoutCache(ctx)
+	}
 
-	price, name, err := s.Steam.FetchDEPrice(ctx, appid)
+	price, name, err := s.Steam.FetchPrice(ctx, appid, cc)
 	if err != nil {
 		return err
 	}
@@ -60,7 +64,7 @@ func (s *PricingService) EnsureSteamDEPriceFresh(ctx context.Context, appid stri
 	return s.Repo.UpsertPriceAndLowest(ctx, repo.UpsertPriceParams{
 		StoreID:         "steam",
 		ExternalGameID:  appid,
-		CC:              "de",
+		CC:              cc,
 		Currency:        price.Currency,
 		InitialCents:    price.InitialCents,
 		FinalCents:      price.FinalCents,
@@ -3,6 +3,7 @@ package jobs
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 
 	"gamedivers.de/api/internal/core/service"
@@ -14,6 +15,31 @@ type DailyUpdater struct {
 	Pricing  *service.PricingService
 	Interval time.Duration
 	Batch    int
+	// StaleAfter is how old a stored price must be before it is refreshed.
+	StaleAfter time.Duration
+	// HistoryRetention is how long price history entries are kept (0 = forever).
+	HistoryRetention time.Duration
+	// Now is the updater's clock (nil = time.Now).
+	Now func() time.Time
+
+	mu    sync.Mutex
+	stats UpdaterStats
+}
+
+// UpdaterStats counts the updater's work since the process started.
+type UpdaterStats struct {
+	Runs        int64     `json:"runs"`
+	Refreshed   int64     `json:"refreshed"`
+	Failed      int64     `json:"failed"`
+	LastRunAt   time.Time `json:"lastRunAt"`
+	LastRefresh int       `json:"lastRunRefreshed"`
+}
+
+// Stats returns a snapshot of the updater's counters.
+func (u *DailyUpdater) Stats() UpdaterStats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.stats
 }
 
 func (u *DailyUpdater) Run(ctx context.Context) {
@@ -32,20 +58,63 @@ func (u *DailyUpdater) Run(ctx context.Context) {
 	}
 }
 
-func (u *DailyUpdater) runOnce(ctx context.Context) {
-	ids, err := u.Repo.ListWatchedUniqueGamesForRefresh(ctx, "steam", "de", u.Batch)
+// runOnce refreshes up to Batch stale prices and reports how many were refreshed.
+func (u *DailyUpdater) runOnce(ctx context.Context) int {
+	now := u.now()
+	u.pruneHistory(ctx, now)
+
+	// Every region a user tracks a game in is refreshed, oldest first.
+	stale, err := u.Repo.ListStaleTrackedPrices(ctx, "steam", now.Add(-u.StaleAfter).Unix(), u.Batch)
 	if err != nil {
-		log.Printf("[daily-updater] list watched unique: %v", err)
-		return
+		log.Printf("[daily-updater] list stale tracked games: %v", err)
+		return 0
 	}
 
-	log.Printf("[daily-updater] refreshing %d watched steam games", len(ids))
-	for _, appid := range ids {
+	log.Printf("[daily-updater] refreshing %d stale steam prices", len(stale))
+	refreshed, failed := 0, 0
+	for _, tp := range stale {
 		if ctx.Err() != nil {
-			return
+			break
 		}
-		if err := u.Pricing.EnsureSteamDEPriceFresh(ctx, appid, true); err != nil {
-			log.Printf("[daily-updater] refresh appid=%s err=%v", appid, err)
+		// The Steam client's limiter paces these calls.
+		if err := u.Pricing.EnsureSteamPriceFresh(ctx, tp.ExternalGameID, tp.CC, true); err != nil {
+			failed++
+			log.Printf("[daily-updater] refresh appid=%s cc=%s err=%v", tp.ExternalGameID, tp.CC, err)
+			continue
 		}
+		refreshed++
+	}
+
+	u.mu.Lock()
+	u.stats.Runs++
+	u.stats.Refreshed += int64(refreshed)
+	u.stats.Failed += int64(failed)
+	u.stats.LastRunAt = now
+	u.stats.LastRefresh = refreshed
+	u.mu.Unlock()
+
+	return refreshed
+}
+
+func (u *DailyUpdater) pruneHistory(ctx context.Context, now time.Time) {
+	if u.HistoryRetention <= 0 {
+		return
+	}
+
+	before := now.Add(-u.HistoryRetention).Unix()
+	deleted, err := u.Repo.PrunePriceHistory(ctx, before)
+	if err != nil {
+		log.Printf("[daily-updater] prune price history: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[daily-updater] pruned %d price history entries", deleted)
+	}
+}
+
+func (u *DailyUpdater) now() time.Time {
+	if u.Now != nil {
+		return u.Now()
 	}
+	return time.Now()
 }
@@ -0,0 +1,179 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gamedivers.de/api/internal/core/service"
+	"gamedivers.de/api/internal/ports/repo"
+	"gamedivers.de/api/internal/ports/store"
+)
+
+// fakePriceRepo keys prices by "appid|cc".
+type fakePriceRepo struct {
+	repo.Repo
+	fetchedAt map[string]int64
+	history   map[string][]int64
+}
+
+func (f *fakePriceRepo) ListStaleTrackedPrices(_ context.Context, _ string, staleBeforeUnix int64, limit int) ([]repo.TrackedPrice, error) {
+	var keys []string
+	for key, at := range f.fetchedAt {
+		if at < staleBeforeUnix {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	out := make([]repo.TrackedPrice, 0, len(keys))
+	for _, key := range keys {
+		id, cc, _ := strings.Cut(key, "|")
+		out = append(out, repo.TrackedPrice{ExternalGameID: id, CC: cc})
+	}
+	return out, nil
+}
+
+func (f *fakePriceRepo) TrackGame(context.Context, string, string, string, int64) error {
+	return nil
+}
+
+func (f *fakePriceRepo) GetPriceFetchedAt(_ context.Context, _, id, cc string) (int64, bool, error) {
+	at, ok := f.fetchedAt[id+"|"+cc]
+	return at, ok, nil
+}
+
+func (f *fakePriceRepo) UpsertGame(context.Context, repo.UpsertGameParams) error {
+	return nil
+}
+
+func (f *fakePriceRepo) UpsertPriceAndLowest(_ context.Context, p repo.UpsertPriceParams) error {
+	key := p.ExternalGameID + "|" + p.CC
+	f.fetchedAt[key] = p.FetchedAtUnix
+	f.history[key] = append(f.history[key], p.FinalCents)
+	return nil
+}
+
+func (f *fakePriceRepo) PrunePriceHistory(context.Context, int64) (int64, error) {
+	return 0, nil
+}
+
+type fakeSteamPrices struct {
+	calls   []string
+	regions []string
+}
+
+func (f *fakeSteamPrices) StoreID() string { return "steam" }
+
+func (f *fakeSteamPrices) FetchPrice(_ context.Context, id, cc string) (*store.Price, string, error) {
+	f.calls = append(f.calls, id)
+	f.regions = append(f.regions, cc)
+	return &store.Price{Currency: "EUR", InitialCents: 1999, FinalCents: 999, DiscountPercent: 50}, "Game " + id, nil
+}
+
+func TestDailyUpdaterRefreshesOnlyStalePrices(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	clock := func() time.Time { return now }
+
+	r := &fakePriceRepo{
+		fetchedAt: map[string]int64{
+			"10|de": now.Add(-30 * time.Hour).Unix(),
+			"20|de": now.Add(-2 * time.Hour).Unix(),
+		},
+		history: map[string][]int64{},
+	}
+	steam := &fakeSteamPrices{}
+	u := &DailyUpdater{
+		Repo: r,
+		Pricing: &service.PricingService{
+			Repo:    r,
+			Steam:   steam,
+			TTL:     time.Hour,
+			NowUnix: func() int64 { return clock().Unix() },
+		},
+		Batch:      10,
+		StaleAfter: 24 * time.Hour,
+		Now:        clock,
+	}
+
+	if got := u.runOnce(context.Background()); got != 1 {
+		t.Fatalf("first run refreshed %d, want 1", got)
+	}
+	if len(steam.calls) != 1 || steam.calls[0] != "10" {
+		t.Fatalf("fetched %v, want only the stale app 10", steam.calls)
+	}
+	if r.fetchedAt["10|de"] != now.Unix() || len(r.history["10|de"]) != 1 {
+		t.Fatalf("app 10 not updated: fetchedAt=%d history=%v", r.fetchedAt["10|de"], r.history["10|de"])
+	}
+
+	// A day later app 20 has gone stale while app 10 is fresh again.
+	now = now.Add(23 * time.Hour)
+	if got := u.runOnce(context.Background()); got != 1 {
+		t.Fatalf("second run refreshed %d, want 1", got)
+	}
+	if len(steam.calls) != 2 || steam.calls[1] != "20" {
+		t.Fatalf("fetched %v, want app 20 on the second run", steam.calls)
+	}
+
+	stats := u.Stats()
+	if stats.Runs != 2 || stats.Refreshed != 2 || stats.Failed != 0 || !stats.LastRunAt.Equal(now) {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestDailyUpdaterBoundsBatch(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	r := &fakePriceRepo{fetchedAt: map[string]int64{}, history: map[string][]int64{}}
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		r.fetchedAt[id+"|de"] = 0
+	}
+	steam := &fakeSteamPrices{}
+	u := &DailyUpdater{
+		Repo:       r,
+		Pricing:    &service.PricingService{Repo: r, Steam: steam, NowUnix: func() int64 { return now.Unix() }},
+		Batch:      2,
+		StaleAfter: time.Hour,
+		Now:        func() time.Time { return now },
+	}
+
+	if got := u.runOnce(context.Background()); got != 2 {
+		t.Fatalf("refreshed %d, want batch of 2", got)
+	}
+	if len(steam.calls) != 2 {
+		t.Fatalf("fetched %v, want 2 calls", steam.calls)
+	}
+}
+
+func TestDailyUpdaterRefreshesEveryTrackedRegion(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	r := &fakePriceRepo{
+		fetchedAt: map[string]int64{"620|de": 0, "620|us": 0, "440|gb": 0},
+		history:   map[string][]int64{},
+	}
+	steam := &fakeSteamPrices{}
+	u := &DailyUpdater{
+		Repo:       r,
+		Pricing:    &service.PricingService{Repo: r, Steam: steam, NowUnix: func() int64 { return now.Unix() }},
+		Batch:      10,
+		StaleAfter: time.Hour,
+		Now:        func() time.Time { return now },
+	}
+
+	if got := u.runOnce(context.Background()); got != 3 {
+		t.Fatalf("refreshed %d, want 3", got)
+	}
+	for _, key := range []string{"620|de", "620|us", "440|gb"} {
+		if r.fetchedAt[key] != now.Unix() {
+			t.Errorf("expected %s to be refreshed", key)
+		}
+	}
+	sort.Strings(steam.regions)
+	if strings.Join(steam.regions, ",") != "de,gb,us" {
+		t.Fatalf("fetched regions %v, want de, gb and us", steam.regions)
+	}
+}
@@ -6,81 +6,180 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"gamedivers.de/api/db/migrations"
 )
 
 const advisoryLockID int64 = 42424242
 
+// AppliedMigration is a row of schema_migrations.
+type AppliedMigration struct {
+	Version   string
+	AppliedAt time.Time
+}
+
+// Status lists applied migrations and embedded ones that haven't run yet.
+type Status struct {
+	Applied []AppliedMigration
+	Pending []string
+}
+
+// Runner applies the embedded migrations against DB and reports their state.
+type Runner struct {
+	DB *sql.DB
+}
+
+// Run applies all pending migrations.
 func Run(ctx context.Context, db *sql.DB) error {
-	if _, err := db.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
-		return fmt.Errorf("acquire advisory lock: %w", err)
+	_, err := Apply(ctx, db)
+	return err
+}
+
+// Apply runs all pending migrations, each in its own transaction, and returns how many
+// were newly applied. Concurrent callers are serialized with an advisory lock, so
+// re-running it is safe.
+func Apply(ctx context.Context, db *sql.DB) (int, error) {
+	// Session-level advisory locks belong to one connection, so pin one for lock and unlock.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return 0, fmt.Errorf("acquire advisory lock: %w", err)
 	}
 	defer func() {
-		_, _ = db.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
 	}()
 
-	if _, err := db.ExecContext(ctx, `
-CREATE TABLE IF NOT EXISTS schema_migrations (
-  version TEXT PRIMARY KEY,
-  applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
-)`); err != nil {
-		return fmt.Errorf("ensure schema_migrations: %w", err)
+	if err := ensureTable(ctx, conn); err != nil {
+		return 0, err
 	}
 
-	entries, err := migrations.FS.ReadDir(".")
+	names, err := embeddedMigrations()
 	if err != nil {
-		return fmt.Errorf("read migrations dir: %w", err)
+		return 0, err
 	}
 
-	var names []string
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		if !strings.HasSuffix(name, ".sql") {
-			continue
-		}
-		names = append(names, name)
-	}
-	sort.Strings(names)
-
+	applied := 0
 	for _, name := range names {
 		// Skip if already applied
 		var exists int
-		err := db.QueryRowContext(ctx, `SELECT 1 FROM schema_migrations WHERE version=$1`, name).Scan(&exists)
+		err := conn.QueryRowContext(ctx, `SELECT 1 FROM schema_migrations WHERE version=$1`, name).Scan(&exists)
 		if err == nil {
 			continue
 		}
 		if err != sql.ErrNoRows {
-			return fmt.Errorf("check migration %s: %w", name, err)
+			return applied, fmt.Errorf("check migration %s: %w", name, err)
 		}
 
 		b, err := migrations.FS.ReadFile(name)
 		if err != nil {
-			return fmt.Errorf("read migration %s: %w", name, err)
+			return applied, fmt.Errorf("read migration %s: %w", name, err)
 		}
 
-		tx, err := db.BeginTx(ctx, nil)
+		tx, err := conn.BeginTx(ctx, nil)
 		if err != nil {
-			return fmt.Errorf("begin tx for %s: %w", name, err)
+			return applied, fmt.Errorf("begin tx for %s: %w", name, err)
 		}
 
 		if _, err := tx.ExecContext(ctx, string(b)); err != nil {
 			_ = tx.Rollback()
-			return fmt.Errorf("exec migration %s: %w", name, err)
+			return applied, fmt.Errorf("exec migration %s: %w", name, err)
 		}
 
 		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations(version) VALUES ($1)`, name); err != nil {
 			_ = tx.Rollback()
-			return fmt.Errorf("record migration %s: %w", name, err)
+			return applied, fmt.Errorf("record migration %s: %w", name, err)
 		}
 
 		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit migration %s: %w", name, err)
+			return applied, fmt.Errorf("commit migration %s: %w", name, err)
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// Run applies all pending migrations and returns how many were newly applied.
+func (r *Runner) Run(ctx context.Context) (int, error) {
+	return Apply(ctx, r.DB)
+}
+
+// Status reports applied versions (oldest first) and pending embedded migrations.
+func (r *Runner) Status(ctx context.Context) (Status, error) {
+	if err := ensureTable(ctx, r.DB); err != nil {
+		return Status{}, err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations ORDER BY applied_at, version`)
+	if err != nil {
+		return Status{}, fmt.Errorf("list migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var status Status
+	done := map[string]struct{}{}
+	for rows.Next() {
+		var m AppliedMigration
+		if err := rows.Scan(&m.Version, &m.AppliedAt); err != nil {
+			return Status{}, fmt.Errorf("scan migration: %w", err)
+		}
+		done[m.Version] = struct{}{}
+		status.Applied = append(status.Applied, m)
+	}
+	if err := rows.Err(); err != nil {
+		return Status{}, fmt.Errorf("list migrations: %w", err)
+	}
+
+	names, err := embeddedMigrations()
+	if err != nil {
+		return Status{}, err
+	}
+	for _, name := range names {
+		if _, ok := done[name]; !ok {
+			status.Pending = append(status.Pending, name)
 		}
 	}
+	return status, nil
+}
 
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func ensureTable(ctx context.Context, db execer) error {
+	if _, err := db.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version TEXT PRIMARY KEY,
+  applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`); err != nil {
+		return fmt.Errorf("ensure schema_migrations: %w", err)
+	}
 	return nil
 }
+
+// embeddedMigrations returns the embedded .sql files in the order they are applied.
+func embeddedMigrations() ([]string, error) {
+	entries, err := migrations.FS.ReadDir(".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
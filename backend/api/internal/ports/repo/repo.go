@@ -21,6 +21,15 @@ type UpsertPriceParams struct {
 	FetchedAtUnix   int64
 }
 
+type UpsertAchievementParams struct {
+	UserID         string
+	StoreID        string
+	ExternalGameID string
+	Unlocked       int
+	Total          int
+	UpdatedAtUnix  int64
+}
+
 type PriceRow struct {
 	StoreID         string `json:"store_id"`
 	ExternalGameID  string `json:"external_game_id"`
@@ -34,16 +43,126 @@ type PriceRow struct {
 	LowestAtUnix    *int64 `json:"lowest_at_unix,omitempty"`
 }
 
+// TrackedPrice is a tracked game's price in one region.
+type TrackedPrice struct {
+	ExternalGameID string
+	CC             string
+}
+
+// PricePoint is one entry of a game's price history.
+type PricePoint struct {
+	Currency        string `json:"currency,omitempty"`
+	InitialCents    int64  `json:"initial_cents"`
+	FinalCents      int64  `json:"final_cents"`
+	DiscountPercent int    `json:"discount_percent"`
+	RecordedAtUnix  int64  `json:"recorded_at_unix"`
+}
+
+// CatalogRow is one game joined with one of its stored prices (price fields are nil for games without prices).
+type CatalogRow struct {
+	StoreID         string
+	ExternalGameID  string
+	Name            string
+	Type            string
+	CC              string
+	Currency        string
+	InitialCents    *int64
+	FinalCents      *int64
+	DiscountPercent *int64
+	LowestCents     *int64
+	FetchedAtUnix   *int64
+}
+
+// CatalogRepo streams the full game catalog without loading it into memory
+type CatalogRepo interface {
+	ForEachCatalogRow(ctx context.Context, fn func(CatalogRow) error) error
+}
+
+// APIToken is a personal access token record. The plaintext token is never stored.
+type APIToken struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"-"`
+	Label      string   `json:"label"`
+	Scopes     []string `json:"scopes"`
+	CreatedAt  int64    `json:"createdAt"`
+	LastUsedAt *int64   `json:"lastUsedAt,omitempty"`
+}
+
+type CreateAPITokenParams struct {
+	ID            string
+	UserID        string
+	TokenHash     string
+	Label         string
+	Scopes        []string
+	CreatedAtUnix int64
+}
+
+// APITokenRepo handles personal access token persistence
+type APITokenRepo interface {
+	CreateAPIToken(ctx context.Context, p CreateAPITokenParams) error
+	ListAPITokens(ctx context.Context, userID string) ([]APIToken, error)
+	RevokeAPIToken(ctx context.Context, userID, tokenID string) (bool, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*APIToken, bool, error)
+	TouchAPIToken(ctx context.Context, tokenID string, nowUnix int64) error
+
+	PutAuthExchange(ctx context.Context, rec AuthExchangeRecord, nowUnix int64) error
+	TakeAuthExchange(ctx context.Context, codeHash string, nowUnix int64) (*AuthExchangeRecord, bool, error)
+
+	PutOAuthState(ctx context.Context, rec OAuthStateRecord, nowUnix int64) error
+	TakeOAuthState(ctx context.Context, state string, nowUnix int64) (*OAuthStateRecord, bool, error)
+	CountOAuthStates(ctx context.Context, nowUnix int64) (int, error)
+}
+
+// OAuthStateRecord is an issued OAuth/OpenID state value. UserID is empty for plain logins.
+type OAuthStateRecord struct {
+	State         string
+	Provider      string
+	UserID        string
+	ExpiresAtUnix int64
+}
+
+// OAuthStateRepo keeps issued OAuth state values in the database so any API replica can serve the callback
+type OAuthStateRepo interface {
+	// PutOAuthState stores rec and drops states that expired before nowUnix.
+	PutOAuthState(ctx context.Context, rec OAuthStateRecord, nowUnix int64) error
+	// TakeOAuthState deletes and returns the record for state; expired records report false.
+	TakeOAuthState(ctx context.Context, state string, nowUnix int64) (*OAuthStateRecord, bool, error)
+	// CountOAuthStates returns how many stored states have not expired at nowUnix.
+	CountOAuthStates(ctx context.Context, nowUnix int64) (int, error)
+}
+
+// AuthExchangeRecord is a stored one-time login exchange. CodeHash is the SHA-256 hex digest of the code;
+// SealedAccessToken is the provider token encrypted with a key only the code holder can derive.
+type AuthExchangeRecord struct {
+	CodeHash          string
+	AccountID         string
+	Username          string
+	SealedAccessToken string
+	ExpiresAtUnix     int64
+}
+
+// AuthExchangeRepo keeps login exchange codes in the database so any API replica can redeem them
+type AuthExchangeRepo interface {
+	// PutAuthExchange stores rec and drops records that expired before nowUnix.
+	PutAuthExchange(ctx context.Context, rec AuthExchangeRecord, nowUnix int64) error
+	// TakeAuthExchange deletes and returns the record for codeHash; expired records report false.
+	TakeAuthExchange(ctx context.Context, codeHash string, nowUnix int64) (*AuthExchangeRecord, bool, error)
+}
+
 // UserRepo handles user-related database operations
 type UserRepo interface {
 	UpsertUser(ctx context.Context, userID string, nowUnix int64) error
 	GetUser(ctx context.Context, userID string) (*User, error)
+	SetUserCountry(ctx context.Context, userID, country string, nowUnix int64) error
+	// DeleteUserData removes the user and every row they own in one transaction.
+	DeleteUserData(ctx context.Context, userID string) error
 }
 
 // User represents a user in the database
 type User struct {
 	ID        string `json:"id"`
 	CreatedAt int64  `json:"created_at"`
+	Country   string `json:"country,omitempty"`
 }
 
 type Repo interface {
@@ -53,11 +172,41 @@ type Repo interface {
 	GetPriceFetchedAt(ctx context.Context, storeID, externalGameID, cc string) (fetchedAtUnix int64, found bool, err error)
 	UpsertPriceAndLowest(ctx context.Context, p UpsertPriceParams) error
 	GetPriceRow(ctx context.Context, storeID, externalGameID, cc string) (*PriceRow, bool, error)
+	// ListCurrentPrices returns the stored current final price in cents for each of the given games that has one.
+	ListCurrentPrices(ctx context.Context, storeID, cc string, externalGameIDs []string) (map[string]int64, error)
+	// ListPriceHistory returns price changes recorded at or after sinceUnix, oldest first, preceded by
+	// the latest change before sinceUnix (the price in effect when the window starts) if there is one.
+	ListPriceHistory(ctx context.Context, storeID, externalGameID, cc string, sinceUnix int64) ([]PricePoint, error)
+	// PrunePriceHistory deletes entries older than beforeUnix, keeping each game's latest entry.
+	PrunePriceHistory(ctx context.Context, beforeUnix int64) (int64, error)
 
 	UpsertUser(ctx context.Context, userID string, nowUnix int64) error
 	GetUser(ctx context.Context, userID string) (*User, error)
+	SetUserCountry(ctx context.Context, userID, country string, nowUnix int64) error
+	DeleteUserData(ctx context.Context, userID string) error
 	AddWatch(ctx context.Context, userID, storeID, externalGameID, cc string, nowUnix int64) error
+	// AddWatchBatch watches and tracks all externalGameIDs with multi-row inserts in one transaction.
+	AddWatchBatch(ctx context.Context, userID, storeID string, externalGameIDs []string, cc string, nowUnix int64) error
 	RemoveWatch(ctx context.Context, userID, storeID, externalGameID, cc string) error
 
-	ListWatchedUniqueGamesForRefresh(ctx context.Context, storeID, cc string, limit int) ([]string, error)
+	// ListStaleTrackedPrices returns up to limit tracked games, in every region, whose price was
+	// last checked before staleBeforeUnix (or never), least recently checked first.
+	ListStaleTrackedPrices(ctx context.Context, storeID string, staleBeforeUnix int64, limit int) ([]TrackedPrice, error)
+
+	UpsertAchievementCounts(ctx context.Context, p UpsertAchievementParams) error
+
+	ForEachCatalogRow(ctx context.Context, fn func(CatalogRow) error) error
+
+	CreateAPIToken(ctx context.Context, p CreateAPITokenParams) error
+	ListAPITokens(ctx context.Context, userID string) ([]APIToken, error)
+	RevokeAPIToken(ctx context.Context, userID, tokenID string) (bool, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*APIToken, bool, error)
+	TouchAPIToken(ctx context.Context, tokenID string, nowUnix int64) error
+
+	PutAuthExchange(ctx context.Context, rec AuthExchangeRecord, nowUnix int64) error
+	TakeAuthExchange(ctx context.Context, codeHash string, nowUnix int64) (*AuthExchangeRecord, bool, error)
+
+	PutOAuthState(ctx context.Context, rec OAuthStateRecord, nowUnix int64) error
+	TakeOAuthState(ctx context.Context, state string, nowUnix int64) (*OAuthStateRecord, bool, error)
+	CountOAuthStates(ctx context.Context, nowUnix int64) (int, error)
 }
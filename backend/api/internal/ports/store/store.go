@@ -1,6 +1,12 @@
 package store
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrProviderNotConfigured is returned when a store's API credentials were not set on this server.
+var ErrProviderNotConfigured = errors.New("provider_not_configured")
 
 type Price struct {
 	Currency        string
@@ -11,5 +17,20 @@ type Price struct {
 
 type StoreClient interface {
 	StoreID() string
-	FetchDEPrice(ctx context.Context, externalGameID string) (*Price, string /*name*/, error)
+	// FetchPrice returns the price in the store region cc (ISO 3166-1 alpha-2).
+	// Clients may answer from a short-lived cache unless ctx was marked with WithoutCache.
+	FetchPrice(ctx context.Context, externalGameID, cc string) (*Price, string /*name*/, error)
+}
+
+type noCacheKey struct{}
+
+// WithoutCache marks ctx so FetchPrice goes to the store instead of a client-side cache (forced refreshes).
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// CacheBypassed reports whether ctx was marked with WithoutCache.
+func CacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
 }
@@ -1,4 +1,4 @@
-import { getItadPrices } from '../../../services/api'
+import { getItadPricesBulk } from '../../../services/api'
 import type { ItadDeal, ItadPrice, ItadPricesResponse } from '../../../types'
 import { ITAD_RATE_RETRY_DELAYS_MS } from './constants'
 import type { WishlistDealSummary } from './types'
@@ -89,11 +89,11 @@ export function getWishlistStoreDeals(
   return { lowest, steam, epic }
 }
 
-export async function getItadPricesWithRetry(gameId: string, region: string): Promise<ItadPricesResponse> {
+export async function getItadPricesWithRetry(gameIds: string[], region: string): Promise<ItadPricesResponse> {
   let attempt = 0
   while (true) {
     try {
-      return await getItadPrices(gameId, region)
+      return await getItadPricesBulk(gameIds, region)
     } catch (error) {
       if (!isRateLimitedError(error) || attempt >= ITAD_RATE_RETRY_DELAYS_MS.length) {
         throw error
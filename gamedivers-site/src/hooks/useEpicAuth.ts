@@ -1,5 +1,5 @@
 import { useEffect, useState } from 'react'
-import { API_BASE } from '../services/api'
+import { API_BASE, redeemEpicExchange } from '../services/api'
 import { STORAGE_KEYS } from '../shared/storage/keys'
 import {
   getLocalString,
@@ -15,6 +15,7 @@ type EpicAuth = {
   username: string | null
   accessToken: string | null
   isLoggedIn: boolean
+  error: string | null
   login: () => void
   logout: () => void
 }
@@ -23,6 +24,7 @@ export function useEpicAuth(): EpicAuth {
   const [epicId, setEpicId] = useState<string | null>(null)
   const [username, setUsername] = useState<string | null>(null)
   const [accessToken, setAccessToken] = useState<string | null>(null)
+  const [error, setError] = useState<string | null>(null)
 
   useEffect(() => {
     const queryParams = new URLSearchParams(window.location.search)
@@ -30,19 +32,32 @@ export function useEpicAuth(): EpicAuth {
     const hashParams = new URLSearchParams(hash)
 
     const id = hashParams.get('epicid') || queryParams.get('epicid')
-    const name = hashParams.get('username') || queryParams.get('username')
-    const token = hashParams.get('access_token') || queryParams.get('access_token')
+    const code = hashParams.get('code') || queryParams.get('code')
 
     if (id) {
-      setEpicId(id)
-      setUsername(name || 'Epic User')
-      setAccessToken(token)
+      window.history.replaceState({}, '', window.location.pathname)
 
-      setLocalString(STORAGE_KEYS.epic.id, id)
-      if (name) setLocalString(STORAGE_KEYS.epic.username, name)
-      if (token) setSessionString(STORAGE_KEYS.epic.accessToken, token)
+      // The callback only carries a one-time code; the account counts as connected once it
+      // has been traded for the access token.
+      if (!code) {
+        setError('Epic login failed. Please try again.')
+        return
+      }
+      redeemEpicExchange(code)
+        .then((login) => {
+          setEpicId(login.accountId)
+          setUsername(login.username)
+          setAccessToken(login.accessToken)
+          setError(null)
 
-      window.history.replaceState({}, '', window.location.pathname)
+          setLocalString(STORAGE_KEYS.epic.id, login.accountId)
+          setLocalString(STORAGE_KEYS.epic.username, login.username)
+          setSessionString(STORAGE_KEYS.epic.accessToken, login.accessToken)
+        })
+        .catch((err: unknown) => {
+          console.error('Epic login exchange failed:', err)
+          setError('Epic login failed. Please try again.')
+        })
     } else {
       const storedId = getLocalString(STORAGE_KEYS.epic.id)
       const storedName = getLocalString(STORAGE_KEYS.epic.username)
@@ -60,6 +75,7 @@ export function useEpicAuth(): EpicAuth {
   }, [])
 
   const login = () => {
+    setError(null)
     window.location.href = `${API_BASE}/v1/epic/login`
   }
 
@@ -78,6 +94,7 @@ export function useEpicAuth(): EpicAuth {
     username,
     accessToken,
     isLoggedIn: !!epicId,
+    error,
     login,
     logout,
   }
@@ -1,12 +1,11 @@
 import { useCallback, useEffect, useRef, useState } from 'react'
 import { APP_EVENTS, emitAppEvent } from '../shared/events'
-import type { WishlistItem } from '../types'
+import type { ItadPricesResponse, WishlistItem } from '../types'
 import { idbDel, idbGet, idbSet } from '../utils/idb'
 import {
   CHECK_INTERVAL_MINUTES,
   DEFAULT_CURRENCY,
   ITAD_LOOKUP_CACHE_KEY,
-  ITAD_MIN_REQUEST_INTERVAL_MS,
   NOTIFY_KEY,
   ONEDRIVE_HANDLE_KEY,
   STEAM_NAME_CACHE_KEY,
@@ -53,6 +52,16 @@ import type {
   WishlistDealSummary,
 } from '../features/store/wishlist/types'
 
+type ResolvedWishlistItem = {
+  item: WishlistItem
+  itadId: string | null
+  title: string
+  source: 'itad' | 'steam'
+  steamAppID: ReturnType<typeof getSteamAppIDFromItem>
+  // failed marks items whose ITAD lookup threw; they only get a new lastCheckedAt.
+  failed: boolean
+}
+
 export function useWishlist(region: string) {
   const notificationsSupported = typeof window !== 'undefined' && 'Notification' in window
   const onedriveSupported = typeof window !== 'undefined' && !!getPicker()
@@ -403,19 +412,18 @@ export function useWishlist(region: string) {
       const lookupCache = readItadLookupCache()
       const queryCache = new Map<string, ItadLookupCacheEntry | null>()
       let lookupCacheDirty = false
-      let lastItadRequestAt = 0
-      const updated: WishlistItem[] = []
+      const resolvedItems: ResolvedWishlistItem[] = []
 
       for (const item of itemsRef.current) {
-        try {
-          const source = item.source ?? (item.id.startsWith('steam:') ? 'steam' : 'itad')
-          const steamAppID = getSteamAppIDFromItem(item)
-          const placeholderTitle = isSteamPlaceholderTitle(item.title ?? '')
-          let resolvedTitle = item.title
-          let resolvedSource: 'itad' | 'steam' = source
-          let itadId = item.itadId ?? (source === 'itad' && !item.id.startsWith('steam:') ? item.id : null)
-
-          if (!itadId) {
+        const source = item.source ?? (item.id.startsWith('steam:') ? 'steam' : 'itad')
+        const steamAppID = getSteamAppIDFromItem(item)
+        const placeholderTitle = isSteamPlaceholderTitle(item.title ?? '')
+        let resolvedTitle = item.title
+        let resolvedSource: 'itad' | 'steam' = source
+        let itadId = item.itadId ?? (source === 'itad' && !item.id.startsWith('steam:') ? item.id : null)
+
+        if (!itadId) {
+          try {
             const resolved = await resolveWishlistItemItad(item, lookupCache, queryCache)
             if (resolved?.id) {
               itadId = resolved.id
@@ -425,72 +433,87 @@ export function useWishlist(region: string) {
               }
               lookupCacheDirty = true
             }
-          }
-
-          if (!itadId) {
-            updated.push({
-              ...item,
-              title: resolvedTitle || item.title,
-              source: resolvedSource,
-              steamAppId: steamAppID ?? item.steamAppId,
-              lastCheckedAt: Date.now(),
-            })
+          } catch (error) {
+            console.error('Wishlist price check failed:', error)
+            resolvedItems.push({ item, itadId: null, title: item.title, source, steamAppID, failed: true })
             continue
           }
+        }
 
-          const elapsed = Date.now() - lastItadRequestAt
-          if (lastItadRequestAt > 0 && elapsed < ITAD_MIN_REQUEST_INTERVAL_MS) {
-            await waitFor(ITAD_MIN_REQUEST_INTERVAL_MS - elapsed)
-          }
+        resolvedItems.push({ item, itadId, title: resolvedTitle, source: resolvedSource, steamAppID, failed: false })
+      }
 
-          const prices = await getItadPricesWithRetry(itadId, region)
-          lastItadRequestAt = Date.now()
-
-          const priceItem = normalizePriceItem(prices, itadId)
-          const deals = priceItem?.deals ?? []
-          const bestDeal = pickLowestDeal(deals)
-          const amount = getAmount(bestDeal?.price ?? null)
-          const currency = bestDeal?.price?.currency ?? item.currency ?? DEFAULT_CURRENCY
-          const cut = bestDeal?.cut ?? 0
-          const onSale = cut > 0
-          const belowThreshold =
-            typeof item.threshold === 'number' && amount !== null ? amount <= item.threshold : false
-          const storeDeals = getWishlistStoreDeals(deals, currency)
-          const prioritizedDeals = dedupeDealSummaries(
-            [storeDeals.lowest, storeDeals.steam, storeDeals.epic].filter(
-              (deal): deal is WishlistDealSummary => !!deal,
-            ),
-          ).slice(0, 3)
-
-          if (notificationsEnabled) {
-            if (onSale && !item.onSale) {
-              notify('Sale alert', `${item.title} is on sale (${cut}% off).`)
-            }
-            if (belowThreshold && !item.belowThreshold) {
-              notify('Price alert', `${item.title} is now ${amount?.toFixed(2)} ${currency}.`)
-            }
-          }
+      // One bulk lookup for every resolved game instead of a request per game.
+      const itadIds = resolvedItems.flatMap((entry) => (entry.itadId ? [entry.itadId] : []))
+      let prices: ItadPricesResponse | null = null
+      let pricesFailed = false
+      if (itadIds.length > 0) {
+        try {
+          prices = await getItadPricesWithRetry(itadIds, region)
+        } catch (error) {
+          pricesFailed = true
+          console.error('Wishlist price check failed:', error)
+        }
+      }
 
+      const updated: WishlistItem[] = []
+      for (const { item, itadId, title: resolvedTitle, source: resolvedSource, steamAppID, failed } of resolvedItems) {
+        if (failed || (itadId && pricesFailed)) {
+          updated.push({ ...item, lastCheckedAt: Date.now() })
+          continue
+        }
+
+        if (!itadId) {
           updated.push({
             ...item,
-            id: itadId,
             title: resolvedTitle || item.title,
-            source: 'itad',
+            source: resolvedSource,
             steamAppId: steamAppID ?? item.steamAppId,
-            itadId,
-            lastPrice: amount ?? item.lastPrice,
-            currency,
-            onSale,
-            belowThreshold,
-            lowestDeal: storeDeals.lowest ?? undefined,
-            dealsTop3: prioritizedDeals,
             lastCheckedAt: Date.now(),
           })
-        } catch (error) {
-          lastItadRequestAt = Date.now()
-          console.error('Wishlist price check failed:', error)
-          updated.push({ ...item, lastCheckedAt: Date.now() })
+          continue
         }
+
+        const priceItem = normalizePriceItem(prices, itadId)
+        const deals = priceItem?.deals ?? []
+        const bestDeal = pickLowestDeal(deals)
+        const amount = getAmount(bestDeal?.price ?? null)
+        const currency = bestDeal?.price?.currency ?? item.currency ?? DEFAULT_CURRENCY
+        const cut = bestDeal?.cut ?? 0
+        const onSale = cut > 0
+        const belowThreshold =
+          typeof item.threshold === 'number' && amount !== null ? amount <= item.threshold : false
+        const storeDeals = getWishlistStoreDeals(deals, currency)
+        const prioritizedDeals = dedupeDealSummaries(
+          [storeDeals.lowest, storeDeals.steam, storeDeals.epic].filter(
+            (deal): deal is WishlistDealSummary => !!deal,
+          ),
+        ).slice(0, 3)
+
+        if (notificationsEnabled) {
+          if (onSale && !item.onSale) {
+            notify('Sale alert', `${item.title} is on sale (${cut}% off).`)
+          }
+          if (belowThreshold && !item.belowThreshold) {
+            notify('Price alert', `${item.title} is now ${amount?.toFixed(2)} ${currency}.`)
+          }
+        }
+
+        updated.push({
+          ...item,
+          id: itadId,
+          title: resolvedTitle || item.title,
+          source: 'itad',
+          steamAppId: steamAppID ?? item.steamAppId,
+          itadId,
+          lastPrice: amount ?? item.lastPrice,
+          currency,
+          onSale,
+          belowThreshold,
+          lowestDeal: storeDeals.lowest ?? undefined,
+          dealsTop3: prioritizedDeals,
+          lastCheckedAt: Date.now(),
+        })
       }
 
       if (lookupCacheDirty) {
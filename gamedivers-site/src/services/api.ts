@@ -1,4 +1,4 @@
-import type { AuthResponse, Game, ItadPricesResponse, ItadSearchItem, User } from '../types'
+import type { AuthResponse, Game, ItadPriceItem, ItadPricesResponse, ItadSearchItem, User } from '../types'
 import { STORAGE_KEYS } from '../shared/storage/keys'
 
 export const API_BASE = import.meta.env.VITE_API_BASE || '/api'
@@ -340,6 +340,29 @@ export type SteamWishlistEntry = {
   capsule?: string
 }
 
+export type EpicExchange = {
+  accountId: string
+  username: string
+  accessToken: string
+}
+
+// redeemEpicExchange trades the one-time code from the Epic callback for the login it stands for.
+export async function redeemEpicExchange(code: string): Promise<EpicExchange> {
+  const res = await fetch(`${API_BASE}/v1/epic/exchange`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ code }),
+  })
+  if (!res.ok) {
+    throw new Error(await readResponseErrorMessage(res, `HTTP ${res.status}`))
+  }
+  const data = (await res.json()) as Partial<EpicExchange>
+  if (!data?.accountId || !data.accessToken) {
+    throw new Error('Epic login response was incomplete')
+  }
+  return { accountId: data.accountId, username: data.username || 'Epic User', accessToken: data.accessToken }
+}
+
 export async function fetchSteamWishlist(steamId: string): Promise<SteamWishlistEntry[]> {
   if (!steamId) return []
   const res = await fetchWithAuth(`${API_BASE}/v1/steam/wishlist?steamid=${encodeURIComponent(steamId)}`)
@@ -389,6 +412,31 @@ export async function getItadPrices(gameId: string, country = 'DE'): Promise<Ita
   }
 }
 
+// The backend accepts 500 ids per bulk request; smaller chunks keep the query string under proxy URL limits.
+const ITAD_BULK_PRICES_CHUNK = 150
+
+export async function getItadPricesBulk(gameIds: string[], country = 'DE'): Promise<ItadPricesResponse> {
+  const unique = Array.from(new Set(gameIds.filter((id) => id.trim())))
+  const games: Record<string, ItadPriceItem> = {}
+
+  for (let start = 0; start < unique.length; start += ITAD_BULK_PRICES_CHUNK) {
+    const params = new URLSearchParams({ country })
+    for (const id of unique.slice(start, start + ITAD_BULK_PRICES_CHUNK)) {
+      params.append('id', id)
+    }
+    try {
+      Object.assign(games, await fetchItadJsonWithRetry<Record<string, ItadPriceItem>>(`${API_BASE}/v1/itad/prices?${params}`))
+    } catch (error) {
+      if (error instanceof Error && error.message.trim()) {
+        throw new Error(error.message)
+      }
+      throw new Error('API nicht erreichbar')
+    }
+  }
+
+  return { games }
+}
+
 function launchExternalProtocol(launchUri: string): void {
   if (typeof window === 'undefined' || typeof document === 'undefined') return
 